@@ -2,6 +2,7 @@ package crypto
 
 import (
 	"crypto/hmac"
+	"crypto/sha256"
 	"crypto/sha512"
 )
 
@@ -11,3 +12,10 @@ func HMAC512(key, plainText []byte) []byte {
 	mac.Write([]byte(plainText))
 	return mac.Sum(nil)
 }
+
+// HMAC256 sha256 hashes data with the given key.
+func HMAC256(key, plainText []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(plainText))
+	return mac.Sum(nil)
+}
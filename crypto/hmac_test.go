@@ -16,3 +16,15 @@ func TestHMAC(t *testing.T) {
 		HMAC512(key, []byte(plaintext)),
 	)
 }
+
+func TestHMAC256(t *testing.T) {
+	assert := assert.New(t)
+	key, err := CreateKey(128)
+	assert.Nil(err)
+	plaintext := "123-12-1234"
+	assert.Equal(
+		HMAC256(key, []byte(plaintext)),
+		HMAC256(key, []byte(plaintext)),
+	)
+	assert.Len(HMAC256(key, []byte(plaintext)), 32)
+}
@@ -0,0 +1,273 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+)
+
+// Audit actions.
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// AuditSink writes audit records.
+type AuditSink interface {
+	// WriteAudit writes an audit record for a change.
+	// It is called with the invocation the change was made with, which is in the change's transaction.
+	WriteAudit(*Invocation, AuditRecord) error
+}
+
+// AuditRecord is a record of a change to a row of a type with audited columns.
+//
+// Columns are audited if they are tagged `db:"...,audit"`, and changes are written to the invocation's audit sink,
+// set with `OptAudit`, by `Create`, `Update`, `Upsert` and `Delete`. The row is read before it is changed, in the
+// change's transaction, to compute the changes.
+type AuditRecord struct {
+	Table        string                 `json:"table"`
+	Action       string                 `json:"action"`
+	Key          map[string]interface{} `json:"key"`
+	Actor        string                 `json:"actor,omitempty"`
+	Changes      []AuditChange          `json:"changes"`
+	TimestampUTC time.Time              `json:"timestampUTC"`
+}
+
+// AuditChange is a change to the value of a column.
+type AuditChange struct {
+	Column string      `json:"column"`
+	Old    interface{} `json:"old"`
+	New    interface{} `json:"new"`
+}
+
+// Columns returns the names of the changed columns.
+func (ar AuditRecord) Columns() []string {
+	columns := make([]string, len(ar.Changes))
+	for index, change := range ar.Changes {
+		columns[index] = change.Column
+	}
+	return columns
+}
+
+// NewAuditTable returns an audit sink that inserts records into a table, which
+// can be created with the `migration.AuditTable` migration.
+func NewAuditTable(table string) AuditTable {
+	return AuditTable{Table: table}
+}
+
+// AuditTable is an audit sink that inserts records into a table, in the change's transaction.
+type AuditTable struct {
+	Table string
+}
+
+// WriteAudit implements AuditSink.
+func (at AuditTable) WriteAudit(i *Invocation, record AuditRecord) error {
+	key, err := json.Marshal(record.Key)
+	if err != nil {
+		return Error(err)
+	}
+	changes, err := json.Marshal(record.Changes)
+	if err != nil {
+		return Error(err)
+	}
+	table := at.Table
+	if table == "" {
+		table = DefaultAuditTable
+	}
+	statement := "INSERT INTO " + table + " (table_name, action, key, actor, changes, timestamp_utc) VALUES ($1, $2, $3, $4, $5, $6)"
+	return IgnoreExecResult(i.auditInvocation(table+"_audit_insert").Exec(statement, record.Table, record.Action, string(key), record.Actor, string(changes), record.TimestampUTC))
+}
+
+// NewAuditLog returns an audit sink that triggers `logger.AuditEvent`s.
+func NewAuditLog(log logger.Triggerable) AuditLog {
+	return AuditLog{Log: log}
+}
+
+// AuditLog is an audit sink that triggers a `logger.AuditEvent` for each record, with the
+// actor as the principal, the action as the verb, the table as the noun, the key as the subject,
+// the changed columns as the property, and the changes as extra values.
+type AuditLog struct {
+	Log logger.Triggerable
+}
+
+// WriteAudit implements AuditSink.
+func (al AuditLog) WriteAudit(i *Invocation, record AuditRecord) error {
+	if al.Log == nil {
+		return nil
+	}
+	extra := make(map[string]string, len(record.Changes))
+	for _, change := range record.Changes {
+		extra[change.Column] = fmt.Sprintf("%v -> %v", change.Old, change.New)
+	}
+	al.Log.Trigger(i.Context, logger.NewAuditEvent(record.Actor, record.Action,
+		logger.OptAuditNoun(record.Table),
+		logger.OptAuditSubject(auditKeyString(record.Key)),
+		logger.OptAuditProperty(strings.Join(record.Columns(), ",")),
+		logger.OptAuditExtra(extra),
+	))
+	return nil
+}
+
+// --------------------------------------------------------------------------------
+// helpers
+// --------------------------------------------------------------------------------
+
+// auditColumns returns the audited columns of an object if the invocation has an audit sink.
+func (i *Invocation) auditColumns(object DatabaseMapped) *ColumnCollection {
+	if i.AuditSink == nil {
+		return nil
+	}
+	audited := NewColumnCollection()
+	for _, column := range CachedColumnCollectionFromInstance(object).Columns() {
+		if column.IsAudit {
+			audited.Add(column)
+		}
+	}
+	if audited.Len() == 0 {
+		return nil
+	}
+	return audited
+}
+
+// auditStored reads the stored row for an object, locking it for the rest of the transaction.
+// It returns nil if there is no stored row.
+func (i *Invocation) auditStored(object DatabaseMapped) (stored DatabaseMapped, err error) {
+	read := i.auditInvocation("")
+	read.Unscoped = true
+	var queryBody string
+	if read.Label, queryBody, err = read.generateGet(object); err != nil {
+		return
+	}
+	read.Label = read.Label + "_audit"
+
+	stored = makeNew(ReflectType(object))
+	rows, err := read.Query(queryBody+" FOR UPDATE", CachedColumnCollectionFromInstance(object).PrimaryKeys().ColumnValues(object)...).Do()
+	if err != nil {
+		return
+	}
+	defer func() { err = ex.Nest(err, Error(rows.Close())) }()
+	var found bool
+	if found, err = Out(rows, stored); err != nil || !found {
+		stored = nil
+		return
+	}
+	err = i.decryptColumns(stored)
+	return
+}
+
+// writeAudit writes an audit record for the changes to audited columns from a stored row to an object,
+// if there are any; objects that are deleted are nil.
+func (i *Invocation) writeAudit(action string, audited *ColumnCollection, stored, object DatabaseMapped) error {
+	keyed := object
+	if keyed == nil {
+		keyed = stored
+	}
+	record := AuditRecord{
+		Table:        TableName(keyed),
+		Action:       action,
+		Key:          map[string]interface{}{},
+		Actor:        GetAuditActor(i.Context),
+		TimestampUTC: time.Now().UTC(),
+	}
+	pks := CachedColumnCollectionFromInstance(keyed).PrimaryKeys()
+	for _, pk := range pks.Columns() {
+		record.Key[pk.ColumnName] = auditValue(pk.GetValue(keyed))
+	}
+	for _, column := range audited.Columns() {
+		var oldValue, newValue interface{}
+		if stored != nil {
+			oldValue = auditValue(column.GetValue(stored))
+		}
+		if object != nil {
+			newValue = auditValue(column.GetValue(object))
+		}
+		if auditValuesEqual(oldValue, newValue) {
+			continue
+		}
+		// the values of encrypted columns are not recorded, just that they changed.
+		if column.IsEncrypted {
+			oldValue, newValue = nil, nil
+		}
+		record.Changes = append(record.Changes, AuditChange{Column: column.ColumnName, Old: oldValue, New: newValue})
+	}
+	if len(record.Changes) == 0 {
+		return nil
+	}
+	return i.AuditSink.WriteAudit(i, record)
+}
+
+// auditInvocation returns an invocation for audit statements, that doesn't cancel the context when it finishes.
+func (i *Invocation) auditInvocation(label string) *Invocation {
+	audit := i.relatedInvocation(nil)
+	audit.Label = label
+	return audit
+}
+
+// auditValue returns the value of a column for an audit record, following references and `driver.Valuer`s.
+func auditValue(value interface{}) interface{} {
+	if reflected := reflect.ValueOf(value); reflected.Kind() == reflect.Ptr {
+		if reflected.IsNil() {
+			return nil
+		}
+		value = reflected.Elem().Interface()
+	}
+	if valuer, ok := value.(driver.Valuer); ok {
+		if converted, err := valuer.Value(); err == nil {
+			return converted
+		}
+	}
+	return value
+}
+
+func auditValuesEqual(oldValue, newValue interface{}) bool {
+	if oldTime, ok := oldValue.(time.Time); ok {
+		newTime, ok := newValue.(time.Time)
+		return ok && oldTime.Equal(newTime)
+	}
+	if oldBytes, ok := oldValue.([]byte); ok {
+		newBytes, ok := newValue.([]byte)
+		return ok && bytes.Equal(oldBytes, newBytes)
+	}
+	return reflect.DeepEqual(oldValue, newValue)
+}
+
+func auditKeyString(key map[string]interface{}) string {
+	columns := make([]string, 0, len(key))
+	for column := range key {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	pieces := make([]string, len(columns))
+	for index, column := range columns {
+		pieces[index] = fmt.Sprintf("%s=%v", column, key[column])
+	}
+	return strings.Join(pieces, ",")
+}
+
+type auditActorKey struct{}
+
+// WithAuditActor returns a context with the actor recorded on audit records for changes made with it.
+func WithAuditActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// GetAuditActor returns the audit actor from a context, or an empty string if it's unset.
+func GetAuditActor(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if actor, ok := ctx.Value(auditActorKey{}).(string); ok {
+		return actor
+	}
+	return ""
+}
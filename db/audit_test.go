@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+)
+
+type auditTest struct {
+	ID        int            `db:"id,pk"`
+	Email     string         `db:"email,audit"`
+	Name      sql.NullString `db:"name,audit"`
+	LastLogin *time.Time     `db:"last_login,audit"`
+	Visits    int            `db:"visits"`
+}
+
+func (auditTest) TableName() string { return "audit_test" }
+
+type auditSinkTest struct {
+	Records []AuditRecord
+}
+
+func (ast *auditSinkTest) WriteAudit(_ *Invocation, record AuditRecord) error {
+	ast.Records = append(ast.Records, record)
+	return nil
+}
+
+type auditTriggerableTest struct {
+	Events []logger.Event
+}
+
+func (att *auditTriggerableTest) Trigger(_ context.Context, e logger.Event) {
+	att.Events = append(att.Events, e)
+}
+
+func TestAuditColumns(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(defaultDB().Invoke().auditColumns(&auditTest{}))
+
+	audited := defaultDB().Invoke(OptAudit(&auditSinkTest{})).auditColumns(&auditTest{})
+	assert.NotNil(audited)
+	assert.Equal([]string{"email", "name", "last_login"}, audited.ColumnNames())
+
+	assert.Nil(defaultDB().Invoke(OptAudit(&auditSinkTest{})).auditColumns(&paginationTest{}))
+}
+
+func TestInvocationWriteAudit(t *testing.T) {
+	assert := assert.New(t)
+
+	sink := &auditSinkTest{}
+	i := defaultDB().Invoke(OptAudit(sink), OptContext(WithAuditActor(context.Background(), "test-actor")))
+	audited := i.auditColumns(&auditTest{})
+
+	login := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	sameLogin := login.In(time.FixedZone("test", 3600))
+	stored := &auditTest{ID: 1, Email: "foo@example.com", LastLogin: &login, Visits: 1}
+	object := &auditTest{ID: 1, Email: "bar@example.com", Name: sql.NullString{String: "bar", Valid: true}, LastLogin: &sameLogin, Visits: 2}
+
+	assert.Nil(i.writeAudit(AuditActionUpdate, audited, stored, object))
+	assert.Len(sink.Records, 1)
+	record := sink.Records[0]
+	assert.Equal("audit_test", record.Table)
+	assert.Equal(AuditActionUpdate, record.Action)
+	assert.Equal("test-actor", record.Actor)
+	assert.Equal(map[string]interface{}{"id": 1}, record.Key)
+	assert.False(record.TimestampUTC.IsZero())
+	assert.Equal([]string{"email", "name"}, record.Columns())
+	assert.Equal(AuditChange{Column: "email", Old: "foo@example.com", New: "bar@example.com"}, record.Changes[0])
+	assert.Equal(AuditChange{Column: "name", Old: nil, New: "bar"}, record.Changes[1])
+
+	// unchanged objects aren't recorded.
+	assert.Nil(i.writeAudit(AuditActionUpdate, audited, object, object))
+	assert.Len(sink.Records, 1)
+
+	assert.Nil(i.writeAudit(AuditActionDelete, audited, object, nil))
+	assert.Len(sink.Records, 2)
+	assert.Equal(map[string]interface{}{"id": 1}, sink.Records[1].Key)
+	assert.Len(sink.Records[1].Changes, 3)
+	assert.Nil(sink.Records[1].Changes[0].New)
+}
+
+func TestAuditLog(t *testing.T) {
+	assert := assert.New(t)
+
+	log := &auditTriggerableTest{}
+	record := AuditRecord{
+		Table:   "audit_test",
+		Action:  AuditActionUpdate,
+		Key:     map[string]interface{}{"id": 1, "tenant": "foo"},
+		Actor:   "test-actor",
+		Changes: []AuditChange{{Column: "email", Old: "foo@example.com", New: "bar@example.com"}},
+	}
+	assert.Nil(NewAuditLog(log).WriteAudit(defaultDB().Invoke(), record))
+	assert.Len(log.Events, 1)
+
+	event, ok := log.Events[0].(logger.AuditEvent)
+	assert.True(ok)
+	assert.Equal("test-actor", event.Principal)
+	assert.Equal(AuditActionUpdate, event.Verb)
+	assert.Equal("audit_test", event.Noun)
+	assert.Equal("id=1,tenant=foo", event.Subject)
+	assert.Equal("email", event.Property)
+	assert.Equal("foo@example.com -> bar@example.com", event.Extra["email"])
+
+	assert.Nil(AuditLog{}.WriteAudit(defaultDB().Invoke(), record))
+}
+
+func TestAuditActor(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(GetAuditActor(context.Background()))
+	assert.Equal("test-actor", GetAuditActor(WithAuditActor(context.Background(), "test-actor")))
+}
+
+func TestInvocationAudit(t *testing.T) {
+	assert := assert.New(t)
+	tx, err := defaultDB().Begin()
+	assert.Nil(err)
+	defer tx.Rollback()
+
+	assert.Nil(IgnoreExecResult(defaultDB().Invoke(OptTx(tx)).Exec("CREATE TABLE audit_test (id int primary key, email text not null, name text, last_login timestamp, visits int not null)")))
+
+	sink := &auditSinkTest{}
+	invoke := func() *Invocation {
+		return defaultDB().Invoke(OptTx(tx), OptAudit(sink))
+	}
+
+	object := auditTest{ID: 1, Email: "foo@example.com"}
+	assert.Nil(invoke().Create(&object))
+	object.Email = "bar@example.com"
+	object.Visits = 2
+	updated, err := invoke().Update(&object)
+	assert.Nil(err)
+	assert.True(updated)
+	object.Visits = 3
+	assert.Nil(invoke().Upsert(&object))
+	assert.Nil(invoke().Upsert(&auditTest{ID: 2, Email: "baz@example.com"}))
+	deleted, err := invoke().Delete(&object)
+	assert.Nil(err)
+	assert.True(deleted)
+
+	assert.Len(sink.Records, 4)
+	assert.Equal(AuditActionCreate, sink.Records[0].Action)
+	assert.Equal(AuditActionUpdate, sink.Records[1].Action)
+	assert.Equal([]AuditChange{{Column: "email", Old: "foo@example.com", New: "bar@example.com"}}, sink.Records[1].Changes)
+	assert.Equal(AuditActionCreate, sink.Records[2].Action)
+	assert.Equal(map[string]interface{}{"id": 2}, sink.Records[2].Key)
+	assert.Equal(AuditActionDelete, sink.Records[3].Action)
+	assert.Equal([]string{"email"}, sink.Records[3].Columns())
+}
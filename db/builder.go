@@ -0,0 +1,150 @@
+package db
+
+import (
+	"bytes"
+	"database/sql"
+	"strconv"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// Builder is a type that renders a parameterized statement.
+type Builder interface {
+	// Label returns the statement label used for logging and tracing.
+	Label() string
+	// Build returns the statement and its arguments, or the first error
+	// encountered while composing the statement.
+	Build() (statement string, args []interface{}, err error)
+}
+
+// NewStatement returns a new statement for a given table and its columns.
+func NewStatement(table string, columns *ColumnCollection) *Statement {
+	return &Statement{
+		Table:   table,
+		Columns: columns,
+	}
+}
+
+// Statement is a parameterized statement being composed by a builder.
+//
+// Column references are checked against the columns of the table; the first
+// unknown column (or other error) is recorded on `Err`, and subsequent writes
+// are ignored.
+type Statement struct {
+	Table   string
+	Columns *ColumnCollection
+	Args    []interface{}
+	Err     error
+
+	buffer bytes.Buffer
+}
+
+// WriteString writes raw sql to the statement.
+func (s *Statement) WriteString(sql string) {
+	if s.Err != nil {
+		return
+	}
+	s.buffer.WriteString(sql)
+}
+
+// WriteColumn writes a column name to the statement, validating that the column exists.
+func (s *Statement) WriteColumn(name string) {
+	if s.ValidateColumn(name) {
+		s.buffer.WriteString(name)
+	}
+}
+
+// ValidateColumn validates that a column exists without writing it, recording an error if it doesn't.
+// It returns if the column is valid and the statement has no error.
+func (s *Statement) ValidateColumn(name string) bool {
+	if s.Err != nil {
+		return false
+	}
+	if !s.Columns.HasColumn(name) {
+		s.Err = ex.New(ErrColumnNotFound, ex.OptMessagef("table: %s, column: %s", s.Table, name))
+		return false
+	}
+	return true
+}
+
+// WriteColumns writes a comma separated list of column names to the statement.
+func (s *Statement) WriteColumns(names ...string) {
+	for index, name := range names {
+		if index > 0 {
+			s.WriteString(",")
+		}
+		s.WriteColumn(name)
+	}
+}
+
+// WriteArg adds an argument and writes its placeholder, e.g. `$1`, to the statement.
+func (s *Statement) WriteArg(value interface{}) {
+	if s.Err != nil {
+		return
+	}
+	s.Args = append(s.Args, value)
+	s.buffer.WriteString("$" + strconv.Itoa(len(s.Args)))
+}
+
+// WriteWhere writes a where clause joining the predicates with `AND`.
+// It writes nothing if there are no predicates.
+func (s *Statement) WriteWhere(predicates []Predicate) {
+	if len(predicates) == 0 {
+		return
+	}
+	s.WriteString(" WHERE ")
+	And(predicates...)(s)
+}
+
+// WriteReturning writes a returning clause for the given columns.
+// It writes nothing if there are no columns.
+func (s *Statement) WriteReturning(names []string) {
+	if len(names) == 0 {
+		return
+	}
+	s.WriteString(" RETURNING ")
+	s.WriteColumns(names...)
+}
+
+// Build returns the statement, its arguments, and any error.
+func (s *Statement) Build() (string, []interface{}, error) {
+	if s.Err != nil {
+		return "", nil, s.Err
+	}
+	return s.buffer.String(), s.Args, nil
+}
+
+// --------------------------------------------------------------------------------
+// invocation helpers
+// --------------------------------------------------------------------------------
+
+// QueryBuilder returns a query for a built statement.
+// Selects run against the replica if one is set; other statements, e.g. an
+// update with a returning clause, are run against the primary.
+func (i *Invocation) QueryBuilder(b Builder) *Query {
+	if i.Label == "" {
+		i.Label = b.Label()
+	}
+	statement, args, err := b.Build()
+	if err != nil {
+		return &Query{Invocation: i, Err: Error(err)}
+	}
+	if _, isSelect := b.(*SelectBuilder); isSelect {
+		return i.readQuery(statement, args...)
+	}
+	i.usePrimary()
+	markWritten(i.Context)
+	return i.Query(statement, args...)
+}
+
+// ExecBuilder executes a built statement.
+func (i *Invocation) ExecBuilder(b Builder) (sql.Result, error) {
+	if i.Label == "" {
+		i.Label = b.Label()
+	}
+	statement, args, err := b.Build()
+	if err != nil {
+		return nil, Error(err)
+	}
+	return i.Exec(statement, args...)
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+type builderTestObj struct {
+	ID    int               `db:"id,pk,auto"`
+	Email string            `db:"email,uk"`
+	Name  string            `db:"name"`
+	Meta  map[string]string `db:"meta,json"`
+	Total int               `db:"total,readonly"`
+}
+
+func (bto builderTestObj) TableName() string {
+	return "builder_test"
+}
+
+func TestStatement(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewStatement("builder_test", Columns(builderTestObj{}))
+	s.WriteString("SELECT ")
+	s.WriteColumns("id", "email")
+	s.WriteString(" FROM builder_test")
+	s.WriteWhere([]Predicate{Eq("id", 1)})
+	s.WriteReturning(nil)
+	statement, args, err := s.Build()
+	assert.Nil(err)
+	assert.Equal("SELECT id,email FROM builder_test WHERE id = $1", statement)
+	assert.Equal([]interface{}{1}, args)
+
+	s = NewStatement("builder_test", Columns(builderTestObj{}))
+	s.WriteColumns("id", "emial", "name")
+	s.WriteArg(1)
+	_, _, err = s.Build()
+	assert.True(IsColumnNotFound(err))
+	assert.Empty(s.Args, "writes after an error should be ignored")
+}
+
+func TestInvocationQueryBuilderError(t *testing.T) {
+	assert := assert.New(t)
+
+	primary, replica := new(recordingDB), new(recordingDB)
+	i := &Invocation{DB: primary, Replica: replica, Context: context.Background()}
+	found, err := i.QueryBuilder(Select(builderTestObj{}).Where(Eq("emial", "foo@bar.com"))).Any()
+	assert.False(found)
+	assert.True(IsColumnNotFound(err))
+	assert.Equal("builder_test_select", i.Label)
+	assert.Empty(primary.Statements)
+	assert.Empty(replica.Statements)
+
+	ctx := WithReadYourWrites(context.Background())
+	i = &Invocation{DB: primary, Replica: replica, Context: ctx}
+	_, err = i.QueryBuilder(Delete(builderTestObj{}).Where(Eq("emial", "foo@bar.com")).Returning("id")).Any()
+	assert.True(IsColumnNotFound(err))
+	assert.Equal(replica, i.Replica, "a builder that fails to build should not pin the invocation to the primary")
+	assert.False(HasWritten(ctx))
+
+	i = &Invocation{DB: primary, Replica: replica, Context: context.Background(), Label: "label"}
+	_, err = i.ExecBuilder(Update(builderTestObj{}).Set("bogus", 1))
+	assert.True(IsColumnNotFound(err))
+	assert.Equal("label", i.Label)
+}
+
+func TestInvocationQueryBuilderRouting(t *testing.T) {
+	assert := assert.New(t)
+
+	primary, replica := new(recordingDB), new(recordingDB)
+	i := &Invocation{DB: primary, Replica: replica, Context: context.Background()}
+	_, _ = i.QueryBuilder(Select(builderTestObj{}).Columns("id")).Any()
+	assert.Equal([]string{"SELECT id FROM builder_test"}, replica.Statements)
+
+	i = &Invocation{DB: primary, Replica: replica, Context: context.Background()}
+	_, _ = i.QueryBuilder(Delete(builderTestObj{}).Returning("id")).Any()
+	assert.Equal([]string{"DELETE FROM builder_test RETURNING id"}, primary.Statements)
+}
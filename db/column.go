@@ -14,7 +14,11 @@ import (
 // --------------------------------------------------------------------------------
 
 // NewColumnFromFieldTag reads the contents of a field tag, ex: `json:"foo" db:"bar,isprimarykey,isserial"
+// Fields with a `rel` tag are relations rather than columns, and return nil.
 func NewColumnFromFieldTag(field reflect.StructField) *Column {
+	if _, isRelation := field.Tag.Lookup("rel"); isRelation {
+		return nil
+	}
 	db := field.Tag.Get("db")
 	if db != "-" {
 		col := Column{}
@@ -29,7 +33,15 @@ func NewColumnFromFieldTag(field reflect.StructField) *Column {
 			}
 
 			if len(pieces) >= 1 {
-				args := strings.ToLower(strings.Join(pieces[1:], ","))
+				var options []string
+				for _, piece := range pieces[1:] {
+					if strings.HasPrefix(strings.ToLower(piece), "blindindex=") {
+						col.BlindIndexOf = strings.TrimSpace(piece[len("blindindex="):])
+						continue
+					}
+					options = append(options, piece)
+				}
+				args := strings.ToLower(strings.Join(options, ","))
 
 				col.IsPrimaryKey = strings.Contains(args, "pk")
 				col.IsUniqueKey = strings.Contains(args, "uk")
@@ -37,6 +49,10 @@ func NewColumnFromFieldTag(field reflect.StructField) *Column {
 				col.IsReadOnly = strings.Contains(args, "readonly")
 				col.Inline = strings.Contains(args, "inline")
 				col.IsJSON = strings.Contains(args, "json")
+				col.IsVersion = strings.Contains(args, "version")
+				col.IsSoftDelete = strings.Contains(args, "softdelete")
+				col.IsAudit = strings.Contains(args, "audit")
+				col.IsEncrypted = strings.Contains(args, "encrypted")
 			}
 		}
 		return &col
@@ -58,6 +74,11 @@ type Column struct {
 	IsAuto       bool
 	IsReadOnly   bool
 	IsJSON       bool
+	IsVersion    bool
+	IsSoftDelete bool
+	IsAudit      bool
+	IsEncrypted  bool
+	BlindIndexOf string
 	Inline       bool
 }
 
@@ -188,3 +209,20 @@ func (c Column) GetValue(object DatabaseMapped) interface{} {
 	valueField := value.Field(c.Index)
 	return valueField.Interface()
 }
+
+// NextVersion returns the current value of a version column on an object and the value it
+// should be incremented to. The column must be an integer type.
+func (c Column) NextVersion(object interface{}) (current, next interface{}, err error) {
+	field := ReflectValue(object).FieldByName(c.FieldName)
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		current = field.Interface()
+		next = reflect.ValueOf(field.Int() + 1).Convert(field.Type()).Interface()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		current = field.Interface()
+		next = reflect.ValueOf(field.Uint() + 1).Convert(field.Type()).Interface()
+	default:
+		err = ex.New(ErrVersionNotInteger, ex.OptMessagef("field: %s", c.FieldName))
+	}
+	return
+}
@@ -257,6 +257,27 @@ func (cc *ColumnCollection) NotReadOnly() *ColumnCollection {
 	return cc.notReadOnly
 }
 
+// VersionColumn returns the optimistic locking version column, tagged `db:"...,version"`,
+// or nil if the collection doesn't have one.
+func (cc *ColumnCollection) VersionColumn() *Column {
+	for index := range cc.columns {
+		if cc.columns[index].IsVersion {
+			return &cc.columns[index]
+		}
+	}
+	return nil
+}
+
+// SoftDeleteColumn returns the soft delete column, tagged `db:"...,softdelete"`, if any.
+func (cc *ColumnCollection) SoftDeleteColumn() *Column {
+	for index := range cc.columns {
+		if cc.columns[index].IsSoftDelete {
+			return &cc.columns[index]
+		}
+	}
+	return nil
+}
+
 // ColumnNames returns the string names for all the columns in the collection.
 func (cc *ColumnCollection) ColumnNames() []string {
 	if cc == nil {
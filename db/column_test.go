@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
 	"github.com/blend/go-sdk/ref"
 )
 
@@ -281,3 +282,34 @@ func TestGetValue(t *testing.T) {
 	a.NotNil(value)
 	a.Equal(5, value)
 }
+
+type versionedTest struct {
+	ID      string `db:"id,pk"`
+	Name    string `db:"name"`
+	Version int64  `db:"version,version"`
+}
+
+type invalidVersionTest struct {
+	ID      string `db:"id,pk"`
+	Version string `db:"version,version"`
+}
+
+func TestColumnNextVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	cols := CachedColumnCollectionFromInstance(versionedTest{})
+	version := cols.VersionColumn()
+	assert.NotNil(version)
+	assert.True(version.IsVersion)
+	assert.Equal("version", version.ColumnName)
+	assert.False(cols.Lookup()["name"].IsVersion)
+	assert.Nil(CachedColumnCollectionFromInstance(setValueTest{}).VersionColumn())
+
+	current, next, err := version.NextVersion(&versionedTest{Version: 3})
+	assert.Nil(err)
+	assert.Equal(int64(3), current)
+	assert.Equal(int64(4), next)
+
+	_, _, err = CachedColumnCollectionFromInstance(invalidVersionTest{}).VersionColumn().NextVersion(invalidVersionTest{})
+	assert.True(ex.Is(err, ErrVersionNotInteger))
+}
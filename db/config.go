@@ -77,6 +77,7 @@ func NewConfigFromDSN(dsn string) (*Config, error) {
 //	-	DB_MAX_CONNECTIONS   = MaxConnections
 //	-	DB_MAX_LIFETIME      = MaxLifetime
 //	-	DB_BUFFER_POOL_SIZE  = BufferPoolSize
+//	-	DB_REPLICA_DSNS      = ReplicaDSNs     //comma separated
 func NewConfigFromEnv() (config Config, err error) {
 	if err = (&config).Resolve(env.WithVars(context.Background(), env.Env())); err != nil {
 		return
@@ -153,6 +154,9 @@ type Config struct {
 	MaxLifetime time.Duration `json:"maxLifetime,omitempty" yaml:"maxLifetime,omitempty" env:"DB_MAX_LIFETIME"`
 	// BufferPoolSize is the number of query composition buffers to maintain.
 	BufferPoolSize int `json:"bufferPoolSize,omitempty" yaml:"bufferPoolSize,omitempty" env:"DB_BUFFER_POOL_SIZE"`
+	// ReplicaDSNs are fully formed DSNs for read replicas of the primary database.
+	// Read only work is routed to a healthy replica if any are set.
+	ReplicaDSNs []string `json:"replicaDSNs,omitempty" yaml:"replicaDSNs,omitempty" env:"DB_REPLICA_DSNS"`
 }
 
 // IsZero returns if the config is unset.
@@ -179,6 +183,7 @@ func (c *Config) Resolve(ctx context.Context) error {
 		configutil.SetInt(&c.MaxConnections, configutil.Env("DB_MAX_CONNECTIONS"), configutil.Int(c.MaxConnections), configutil.Int(DefaultMaxConnections)),
 		configutil.SetDuration(&c.MaxLifetime, configutil.Env("DB_MAX_LIFETIME"), configutil.Duration(c.MaxLifetime), configutil.Duration(DefaultMaxLifetime)),
 		configutil.SetInt(&c.BufferPoolSize, configutil.Env("DB_BUFFER_POOL_SIZE"), configutil.Int(c.BufferPoolSize), configutil.Int(DefaultBufferPoolSize)),
+		configutil.SetStrings(&c.ReplicaDSNs, configutil.Env("DB_REPLICA_DSNS"), configutil.Strings(c.ReplicaDSNs)),
 	)
 }
 
@@ -68,11 +68,25 @@ type Connection struct {
 	Log                  logger.Log
 	Tracer               Tracer
 	StatementInterceptor StatementInterceptor
+	Replicas             []*Replica
+	ReplicaSelector      ReplicaSelector
+	PlanCache            *PlanCache
+	AuditSink            AuditSink
+	Encrypter            ColumnEncrypter
+	BlindIndexKey        []byte
 }
 
 // Close implements a closer.
 func (dbc *Connection) Close() error {
-	return dbc.Connection.Close()
+	var err error
+	if dbc.PlanCache != nil {
+		err = dbc.PlanCache.Close()
+	}
+	err = ex.Nest(err, dbc.Connection.Close())
+	for _, replica := range dbc.Replicas {
+		err = ex.Nest(err, replica.Close())
+	}
+	return err
 }
 
 // Open returns a connection object, either a cached connection object or creating a new one in the process.
@@ -93,6 +107,13 @@ func (dbc *Connection) Open() error {
 	if err != nil {
 		return err
 	}
+	// validate the replica dsns before opening anything, so a bad dsn doesn't leave pools open.
+	replicaNamedValues := make([]string, len(dbc.Config.ReplicaDSNs))
+	for index, replicaDSN := range dbc.Config.ReplicaDSNs {
+		if replicaNamedValues[index], err = ParseURL(replicaDSN); err != nil {
+			return err
+		}
+	}
 
 	// open the connection
 	dbConn, err := sql.Open(dbc.Config.EngineOrDefault(), namedValues)
@@ -100,13 +121,70 @@ func (dbc *Connection) Open() error {
 		return Error(err)
 	}
 
+	var replicas []*Replica
+	for index, replicaDSN := range dbc.Config.ReplicaDSNs {
+		replicaConn, err := sql.Open(dbc.Config.EngineOrDefault(), replicaNamedValues[index])
+		if err != nil {
+			err = ex.Nest(Error(err), dbConn.Close())
+			for _, replica := range replicas {
+				err = ex.Nest(err, replica.Close())
+			}
+			return err
+		}
+		dbc.configurePool(replicaConn)
+		replica := NewReplica(replicaConn)
+		replica.DSN = replicaDSN
+		replicas = append(replicas, replica)
+	}
+
 	dbc.Connection = dbConn
-	dbc.Connection.SetConnMaxLifetime(dbc.Config.MaxLifetimeOrDefault())
-	dbc.Connection.SetMaxIdleConns(dbc.Config.IdleConnectionsOrDefault())
-	dbc.Connection.SetMaxOpenConns(dbc.Config.MaxConnectionsOrDefault())
+	dbc.configurePool(dbc.Connection)
+	if dbc.PlanCache != nil && dbc.PlanCache.Connection == nil {
+		dbc.PlanCache.Connection = dbConn
+	}
+	dbc.Replicas = append(dbc.Replicas, replicas...)
+	if len(dbc.Replicas) > 0 && dbc.ReplicaSelector == nil {
+		dbc.ReplicaSelector = ReplicaSelectorRoundRobin()
+	}
 	return nil
 }
 
+// configurePool applies the config pool settings to a driver connection.
+func (dbc *Connection) configurePool(conn *sql.DB) {
+	conn.SetConnMaxLifetime(dbc.Config.MaxLifetimeOrDefault())
+	conn.SetMaxIdleConns(dbc.Config.IdleConnectionsOrDefault())
+	conn.SetMaxOpenConns(dbc.Config.MaxConnectionsOrDefault())
+}
+
+// SelectReplica returns a healthy replica chosen by the replica selector, or the
+// first healthy replica if the selector is unset.
+// It returns nil if there are no healthy replicas.
+func (dbc *Connection) SelectReplica() *Replica {
+	var healthy []*Replica
+	for _, replica := range dbc.Replicas {
+		if replica.Healthy() {
+			healthy = append(healthy, replica)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+	if dbc.ReplicaSelector == nil {
+		return healthy[0]
+	}
+	return dbc.ReplicaSelector(healthy)
+}
+
+// CheckReplicas pings each replica and updates its health.
+// Unhealthy replicas are skipped by `SelectReplica` until a later check succeeds,
+// so this should be called periodically, e.g. with an `async.Interval`.
+func (dbc *Connection) CheckReplicas(ctx context.Context) (err error) {
+	for _, replica := range dbc.Replicas {
+		err = ex.Nest(err, replica.Check(ctx))
+	}
+	return
+}
+
 // Begin starts a new transaction.
 func (dbc *Connection) Begin(opts ...func(*sql.TxOptions)) (*sql.Tx, error) {
 	if dbc.Connection == nil {
@@ -151,6 +229,9 @@ func (dbc *Connection) PrepareContext(context context.Context, statement string,
 // --------------------------------------------------------------------------------
 
 // Invoke returns a new invocation.
+// If the connection has healthy replicas, read only work for the invocation
+// is routed to one of them unless it runs in a transaction; the replica is
+// selected when the invocation first does read only work.
 func (dbc *Connection) Invoke(options ...InvocationOption) *Invocation {
 	i := Invocation{
 		DB:                   dbc.Connection,
@@ -160,6 +241,13 @@ func (dbc *Connection) Invoke(options ...InvocationOption) *Invocation {
 		Log:                  dbc.Log,
 		Tracer:               dbc.Tracer,
 		StatementInterceptor: dbc.StatementInterceptor,
+		PlanCache:            dbc.PlanCache,
+		AuditSink:            dbc.AuditSink,
+		Encrypter:            dbc.Encrypter,
+		BlindIndexKey:        dbc.BlindIndexKey,
+	}
+	if len(dbc.Replicas) > 0 {
+		i.selectReplica = dbc.SelectReplica
 	}
 	for _, option := range options {
 		option(&i)
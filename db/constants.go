@@ -53,4 +53,32 @@ const (
 	DefaultMaxLifetime = time.Duration(0)
 	// DefaultBufferPoolSize is the default number of buffer pool entries to maintain.
 	DefaultBufferPoolSize = 1024
+
+	// DefaultTxMaxAttempts is the default number of times `InTx` attempts a transaction.
+	DefaultTxMaxAttempts = 3
+	// DefaultTxRetryDelay is the default base delay between `InTx` attempts.
+	DefaultTxRetryDelay = 50 * time.Millisecond
+	// DefaultCursorBatchSize is the default number of rows a cursor fetches at a time.
+	DefaultCursorBatchSize = 1000
+	// DefaultPaginationLimit is the default maximum number of rows in a page.
+	DefaultPaginationLimit = 100
+	// DefaultAuditTable is the default table the `AuditTable` sink inserts records into.
+	DefaultAuditTable = "audit_log"
+	// DefaultListenerMinReconnectInterval is the default initial delay before a listener reconnects.
+	DefaultListenerMinReconnectInterval = 500 * time.Millisecond
+	// DefaultListenerMaxReconnectInterval is the default maximum delay between listener reconnect attempts.
+	DefaultListenerMaxReconnectInterval = time.Minute
+	// DefaultListenerPingInterval is the default interval a listener pings its connection on.
+	DefaultListenerPingInterval = 90 * time.Second
+	// DefaultLockCheckInterval is the default interval the connection a session scoped lock is held on is checked on.
+	DefaultLockCheckInterval = 10 * time.Second
+	// DefaultPlanCacheNamedLimit is the maximum number of parsed named statements a plan cache keeps.
+	DefaultPlanCacheNamedLimit = 1024
+)
+
+const (
+	// PQCodeSerializationFailure is the postgres error code for serialization failures.
+	PQCodeSerializationFailure = "40001"
+	// PQCodeDeadlockDetected is the postgres error code for detected deadlocks.
+	PQCodeDeadlockDetected = "40P01"
 )
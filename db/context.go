@@ -1,6 +1,9 @@
 package db
 
-import "context"
+import (
+	"context"
+	"sync/atomic"
+)
 
 type skipQueryLogging struct{}
 
@@ -16,3 +19,38 @@ func IsSkipQueryLogging(ctx context.Context) bool {
 	}
 	return false
 }
+
+type readYourWritesKey struct{}
+
+type readYourWrites struct {
+	wrote int32
+}
+
+// WithReadYourWrites returns a context that pins reads to the primary once a write
+// has been made with it (or a context derived from it), so that replica lag can't
+// hide the write from later reads.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readYourWritesKey{}, new(readYourWrites))
+}
+
+// HasWritten returns if a write has been made with a context that was
+// set up with `WithReadYourWrites`.
+func HasWritten(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	if v, ok := ctx.Value(readYourWritesKey{}).(*readYourWrites); ok {
+		return atomic.LoadInt32(&v.wrote) == 1
+	}
+	return false
+}
+
+// markWritten records a write on a context set up with `WithReadYourWrites`.
+func markWritten(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+	if v, ok := ctx.Value(readYourWritesKey{}).(*readYourWrites); ok {
+		atomic.StoreInt32(&v.wrote, 1)
+	}
+}
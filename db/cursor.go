@@ -0,0 +1,248 @@
+package db
+
+import (
+	"database/sql"
+	"reflect"
+	"strconv"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/uuid"
+)
+
+// Cursor returns a cursor that streams the results of a query in batches with a server side cursor.
+//
+// The cursor is declared in the invocation's transaction, or in a read only transaction it
+// begins and ends if the invocation is not in one. The cursor must be closed, either with
+// `Close` or by `Each` and `OutEach` when they return.
+//
+//	cursor := conn.Invoke(db.OptContext(ctx)).Cursor("SELECT * FROM events").WithBatchSize(5000)
+//	err := cursor.OutEach(Event{}, func(obj interface{}) error {
+//		return export(obj.(*Event))
+//	})
+func (i *Invocation) Cursor(statement string, args ...interface{}) *Cursor {
+	return &Cursor{
+		Invocation: i,
+		Statement:  statement,
+		Args:       args,
+		BatchSize:  DefaultCursorBatchSize,
+		Name:       "cursor_" + uuid.V4().String(),
+	}
+}
+
+// Cursor streams the results of a query with a server side cursor.
+//
+// It can be iterated with `Next`, reading each row with `Out` or `Scan`, like `*sql.Rows`.
+type Cursor struct {
+	Invocation *Invocation
+	Statement  string
+	Args       []interface{}
+	// BatchSize is the number of rows fetched at a time.
+	BatchSize int
+	// Name is the name the cursor is declared with.
+	Name string
+
+	tx        *sql.Tx
+	ownsTx    bool
+	declared  bool
+	done      bool
+	closed    bool
+	rows      *sql.Rows
+	batchRows int
+	err       error
+}
+
+// WithBatchSize sets the number of rows fetched at a time.
+func (c *Cursor) WithBatchSize(batchSize int) *Cursor {
+	c.BatchSize = batchSize
+	return c
+}
+
+// Next advances the cursor to the next row, fetching the next batch of rows as needed.
+// It returns false when there are no more rows, or on error; check `Err` for the error.
+func (c *Cursor) Next() bool {
+	if c.err != nil || c.done || c.closed {
+		return false
+	}
+	if !c.declared {
+		if c.err = c.declare(); c.err != nil {
+			return false
+		}
+	}
+	for {
+		if c.err = c.Invocation.Context.Err(); c.err != nil {
+			return false
+		}
+		if c.rows == nil {
+			if c.err = c.fetch(); c.err != nil {
+				return false
+			}
+		}
+		if c.rows.Next() {
+			c.batchRows++
+			return true
+		}
+		if c.err = c.closeRows(); c.err != nil {
+			return false
+		}
+		if c.batchRows < c.BatchSize {
+			c.done = true
+			return false
+		}
+	}
+}
+
+// Out populates an object from the current row, using the column cache.
+func (c *Cursor) Out(object interface{}) error {
+	if c.rows == nil {
+		return Error(ErrCursorNoRow)
+	}
+	if populatable, ok := object.(Populatable); ok {
+		return populatable.Populate(c.rows)
+	}
+	return PopulateByName(object, c.rows, CachedColumnCollectionFromInstance(object))
+}
+
+// Scan scans the current row into the given values.
+func (c *Cursor) Scan(args ...interface{}) error {
+	if c.rows == nil {
+		return Error(ErrCursorNoRow)
+	}
+	return Error(c.rows.Scan(args...))
+}
+
+// Err returns the error, if any, encountered iterating the cursor.
+func (c *Cursor) Err() error {
+	return c.err
+}
+
+// Each calls a consumer for each row, then closes the cursor.
+func (c *Cursor) Each(consumer RowsConsumer) (err error) {
+	defer func() { err = c.close(recover(), err) }()
+	for c.Next() {
+		if err = consumer(c.rows); err != nil {
+			err = Error(err)
+			return
+		}
+	}
+	return
+}
+
+// OutEach populates a new instance of the type of `object` for each row, and calls a consumer
+// with a reference to it, then closes the cursor. It calls the `AfterGet` hook of each instance
+// if its type implements it.
+func (c *Cursor) OutEach(object interface{}, consumer func(interface{}) error) (err error) {
+	defer func() { err = c.close(recover(), err) }()
+
+	objectType := ReflectType(object)
+	if objectType.Kind() != reflect.Struct {
+		err = Error(ErrDestinationNotStruct)
+		return
+	}
+	meta := CachedColumnCollectionFromType(newColumnCacheKey(objectType), objectType)
+	isPopulatable := IsPopulatable(makeNew(objectType))
+
+	for c.Next() {
+		newObj := makeNew(objectType)
+		if isPopulatable {
+			err = AsPopulatable(newObj).Populate(c.rows)
+		} else {
+			err = PopulateByName(newObj, c.rows, meta)
+		}
+		if err != nil {
+			return
+		}
+		if err = c.Invocation.decryptColumns(newObj); err != nil {
+			return
+		}
+		if err = c.Invocation.afterGet(newObj); err != nil {
+			return
+		}
+		if err = consumer(newObj); err != nil {
+			err = Error(err)
+			return
+		}
+	}
+	return
+}
+
+// Close closes the cursor, and ends the transaction it began, if any.
+// It returns the error encountered iterating the cursor, if any.
+func (c *Cursor) Close() error {
+	return c.close(nil, nil)
+}
+
+// --------------------------------------------------------------------------------
+// helpers
+// --------------------------------------------------------------------------------
+
+func (c *Cursor) declare() (err error) {
+	c.declared = true
+	if c.BatchSize <= 0 {
+		c.BatchSize = DefaultCursorBatchSize
+	}
+
+	i := c.Invocation
+	c.Statement = i.Start(c.Statement)
+	switch db := i.ReadDB().(type) {
+	case *sql.Tx:
+		c.tx = db
+	case txBeginner:
+		if c.tx, err = db.BeginTx(i.Context, &sql.TxOptions{ReadOnly: true}); err != nil {
+			err = Error(err)
+			return
+		}
+		c.ownsTx = true
+	default:
+		err = Error(ErrCursorRequiresTx)
+		return
+	}
+
+	if _, err = c.tx.ExecContext(i.Context, "DECLARE "+c.Name+" NO SCROLL CURSOR FOR "+c.Statement, c.Args...); err != nil {
+		err = Error(err)
+	}
+	return
+}
+
+func (c *Cursor) fetch() (err error) {
+	c.batchRows = 0
+	if c.rows, err = c.tx.QueryContext(c.Invocation.Context, "FETCH FORWARD "+strconv.Itoa(c.BatchSize)+" FROM "+c.Name); err != nil {
+		err = Error(err)
+	}
+	return
+}
+
+func (c *Cursor) closeRows() (err error) {
+	if c.rows == nil {
+		return
+	}
+	err = ex.Nest(Error(c.rows.Err()), Error(c.rows.Close()))
+	c.rows = nil
+	return
+}
+
+func (c *Cursor) close(r interface{}, err error) error {
+	if c.closed {
+		return err
+	}
+	c.closed = true
+	if r != nil {
+		err = ex.Nest(err, ex.New(r))
+	}
+	err = ex.Nest(err, c.err, c.closeRows())
+	if c.tx != nil {
+		if c.ownsTx {
+			if err != nil {
+				err = ex.Nest(err, Error(c.tx.Rollback()))
+			} else {
+				err = Error(c.tx.Commit())
+			}
+		} else if c.declared && err == nil {
+			_, closeErr := c.tx.ExecContext(c.Invocation.Context, "CLOSE "+c.Name)
+			err = Error(closeErr)
+		}
+	}
+	if !c.declared {
+		return err
+	}
+	return c.Invocation.Finish(c.Statement, nil, nil, err)
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+type cursorTest struct {
+	ID int `db:"id"`
+}
+
+func TestInvocationCursor(t *testing.T) {
+	assert := assert.New(t)
+
+	cursor := defaultDB().Invoke().Cursor("SELECT 1", "foo")
+	assert.Equal("SELECT 1", cursor.Statement)
+	assert.Equal([]interface{}{"foo"}, cursor.Args)
+	assert.Equal(DefaultCursorBatchSize, cursor.BatchSize)
+	assert.True(strings.HasPrefix(cursor.Name, "cursor_"))
+	assert.Equal(10, cursor.WithBatchSize(10).BatchSize)
+}
+
+func TestCursorRequiresTx(t *testing.T) {
+	assert := assert.New(t)
+
+	db := new(recordingDB)
+	cursor := (&Invocation{DB: db, Context: context.Background()}).Cursor("SELECT 1")
+	assert.True(ex.Is(cursor.Out(&cursorTest{}), ErrCursorNoRow))
+	assert.True(ex.Is(cursor.Scan(), ErrCursorNoRow))
+	assert.False(cursor.Next())
+	assert.True(ex.Is(cursor.Err(), ErrCursorRequiresTx))
+	assert.True(ex.Is(cursor.Close(), ErrCursorRequiresTx))
+	assert.Empty(db.Statements)
+}
+
+func TestCursorOutEachNotStruct(t *testing.T) {
+	assert := assert.New(t)
+
+	cursor := (&Invocation{DB: new(recordingDB), Context: context.Background()}).Cursor("SELECT 1")
+	err := cursor.OutEach(1, func(_ interface{}) error { return nil })
+	assert.True(ex.Is(err, ErrDestinationNotStruct))
+}
+
+func TestCursorOutEach(t *testing.T) {
+	assert := assert.New(t)
+
+	var ids []int
+	err := defaultDB().Invoke().Cursor("SELECT id FROM generate_series(1, $1) as id", 10).WithBatchSize(3).OutEach(cursorTest{}, func(obj interface{}) error {
+		ids = append(ids, obj.(*cursorTest).ID)
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, ids)
+}
+
+func TestCursorNextInTx(t *testing.T) {
+	assert := assert.New(t)
+	tx, err := defaultDB().Begin()
+	assert.Nil(err)
+	defer tx.Rollback()
+
+	cursor := defaultDB().Invoke(OptTx(tx)).Cursor("SELECT id FROM generate_series(1, 4) as id").WithBatchSize(2)
+	var count, sum int
+	for cursor.Next() {
+		var obj cursorTest
+		assert.Nil(cursor.Out(&obj))
+		count++
+		sum += obj.ID
+	}
+	assert.Nil(cursor.Err())
+	assert.Nil(cursor.Close())
+	assert.Equal(4, count)
+	assert.Equal(10, sum)
+
+	// the transaction should still be usable once the cursor is closed.
+	assert.Nil(IgnoreExecResult(defaultDB().Invoke(OptTx(tx)).Exec("SELECT 1")))
+}
+
+func TestCursorEachCancel(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var count int
+	err := defaultDB().Invoke(OptContext(ctx)).Cursor("SELECT id FROM generate_series(1, 100) as id").WithBatchSize(10).Each(func(r Rows) error {
+		count++
+		if count == 5 {
+			cancel()
+		}
+		return nil
+	})
+	assert.True(ex.Is(err, context.Canceled))
+	assert.Equal(5, count)
+}
@@ -0,0 +1,215 @@
+package dbstats
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/mathutil"
+	"github.com/blend/go-sdk/stringutil"
+)
+
+// New returns a new aggregator.
+func New(options ...Option) *Aggregator {
+	a := Aggregator{
+		SampleSize: DefaultSampleSize,
+		since:      time.Now().UTC(),
+		statements: map[string]*statement{},
+	}
+	for _, option := range options {
+		option(&a)
+	}
+	return &a
+}
+
+// Option mutates an aggregator.
+type Option func(*Aggregator)
+
+// OptSlowThreshold sets the elapsed time over which queries are logged as slow queries.
+func OptSlowThreshold(threshold time.Duration) Option {
+	return func(a *Aggregator) { a.SlowThreshold = threshold }
+}
+
+// OptLog sets the logger slow query events are triggered on.
+func OptLog(log logger.Triggerable) Option {
+	return func(a *Aggregator) { a.Log = log }
+}
+
+// OptRedact sets a function that redacts query bodies in slow query events.
+func OptRedact(redact func(string) string) Option {
+	return func(a *Aggregator) { a.Redact = redact }
+}
+
+// OptSampleSize sets the number of recent latencies kept per statement to compute percentiles.
+// Sizes less than zero are treated as zero, which disables percentiles.
+func OptSampleSize(sampleSize int) Option {
+	return func(a *Aggregator) {
+		if sampleSize < 0 {
+			sampleSize = 0
+		}
+		a.SampleSize = sampleSize
+	}
+}
+
+// OptWindow sets the period after which the statistics are reset.
+func OptWindow(window time.Duration) Option {
+	return func(a *Aggregator) { a.Window = window }
+}
+
+// Aggregator aggregates statistics per statement label from query events.
+//
+// Unlabeled statements are aggregated by their (redacted) body.
+type Aggregator struct {
+	sync.Mutex
+	SlowThreshold time.Duration
+	Log           logger.Triggerable
+	Redact        func(string) string
+	SampleSize    int
+	Window        time.Duration
+
+	since      time.Time
+	statements map[string]*statement
+}
+
+// Add records a query event, and triggers a slow query event on the logger if it exceeds the slow threshold.
+func (a *Aggregator) Add(ctx context.Context, qe db.QueryEvent) {
+	if a.SlowThreshold > 0 && qe.Elapsed > a.SlowThreshold && a.Log != nil {
+		slow := qe
+		slow.Body = a.redact(qe.Body)
+		a.Log.Trigger(ctx, db.NewSlowQueryEvent(slow, a.SlowThreshold))
+	}
+
+	key := qe.Label
+	if key == "" {
+		key = stringutil.CompressSpace(a.redact(qe.Body))
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	a.resetIfElapsed()
+	s, ok := a.statements[key]
+	if !ok {
+		s = &statement{samples: make([]time.Duration, 0, a.SampleSize)}
+		a.statements[key] = s
+	}
+	s.Count++
+	if qe.Err != nil {
+		s.Errors++
+	}
+	s.Rows += qe.RowsAffected
+	s.Total += qe.Elapsed
+	if qe.Elapsed > s.Max {
+		s.Max = qe.Elapsed
+	}
+	if a.SampleSize > 0 {
+		if len(s.samples) < a.SampleSize {
+			s.samples = append(s.samples, qe.Elapsed)
+		} else {
+			s.samples[s.next] = qe.Elapsed
+			s.next = (s.next + 1) % a.SampleSize
+		}
+	}
+}
+
+// Snapshot returns the statistics for each statement, ordered by total elapsed time, descending.
+func (a *Aggregator) Snapshot() Snapshot {
+	a.Lock()
+	defer a.Unlock()
+	a.resetIfElapsed()
+
+	snapshot := Snapshot{
+		Since:      a.since,
+		Statements: make([]StatementStats, 0, len(a.statements)),
+	}
+	for key, s := range a.statements {
+		stats := StatementStats{
+			Statement: key,
+			Count:     s.Count,
+			Errors:    s.Errors,
+			Rows:      s.Rows,
+			Total:     s.Total,
+			Max:       s.Max,
+		}
+		if s.Count > 0 {
+			stats.Mean = s.Total / time.Duration(s.Count)
+		}
+		if len(s.samples) > 0 {
+			sorted := mathutil.CopySortDurations(s.samples)
+			stats.P50 = mathutil.PercentileSortedDurations(sorted, 50)
+			stats.P95 = mathutil.PercentileSortedDurations(sorted, 95)
+			stats.P99 = mathutil.PercentileSortedDurations(sorted, 99)
+		}
+		snapshot.Statements = append(snapshot.Statements, stats)
+	}
+	sort.Slice(snapshot.Statements, func(i, j int) bool {
+		if snapshot.Statements[i].Total == snapshot.Statements[j].Total {
+			return snapshot.Statements[i].Statement < snapshot.Statements[j].Statement
+		}
+		return snapshot.Statements[i].Total > snapshot.Statements[j].Total
+	})
+	return snapshot
+}
+
+// Reset clears the statistics.
+func (a *Aggregator) Reset() {
+	a.Lock()
+	defer a.Unlock()
+	a.reset()
+}
+
+// Snapshot is the statistics of each statement since a time.
+type Snapshot struct {
+	Since      time.Time        `json:"since"`
+	Statements []StatementStats `json:"statements"`
+}
+
+// StatementStats are the statistics for a statement.
+//
+// Percentiles are computed from a sample of recent latencies.
+type StatementStats struct {
+	Statement string        `json:"statement"`
+	Count     int64         `json:"count"`
+	Errors    int64         `json:"errors"`
+	Rows      int64         `json:"rows"`
+	Total     time.Duration `json:"total"`
+	Mean      time.Duration `json:"mean"`
+	P50       time.Duration `json:"p50"`
+	P95       time.Duration `json:"p95"`
+	P99       time.Duration `json:"p99"`
+	Max       time.Duration `json:"max"`
+}
+
+// --------------------------------------------------------------------------------
+// helpers
+// --------------------------------------------------------------------------------
+
+type statement struct {
+	Count   int64
+	Errors  int64
+	Rows    int64
+	Total   time.Duration
+	Max     time.Duration
+	samples []time.Duration
+	next    int
+}
+
+func (a *Aggregator) redact(body string) string {
+	if a.Redact != nil {
+		return a.Redact(body)
+	}
+	return body
+}
+
+func (a *Aggregator) resetIfElapsed() {
+	if a.Window > 0 && time.Now().UTC().Sub(a.since) > a.Window {
+		a.reset()
+	}
+}
+
+func (a *Aggregator) reset() {
+	a.since = time.Now().UTC()
+	a.statements = map[string]*statement{}
+}
@@ -0,0 +1,111 @@
+package dbstats
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/logger"
+)
+
+func TestAggregator(t *testing.T) {
+	assert := assert.New(t)
+
+	a := New()
+	for index := 1; index <= 100; index++ {
+		a.Add(context.Background(), db.NewQueryEvent("select * from users", time.Duration(index)*time.Millisecond, db.OptQueryLabel("get_users"), db.OptQueryRowsAffected(2)))
+	}
+	a.Add(context.Background(), db.NewQueryEvent("delete from users", time.Second, db.OptQueryLabel("delete_users"), db.OptQueryErr(fmt.Errorf("this is only a test"))))
+
+	snapshot := a.Snapshot()
+	assert.False(snapshot.Since.IsZero())
+	assert.Len(snapshot.Statements, 2)
+
+	getUsers := snapshot.Statements[0]
+	assert.Equal("get_users", getUsers.Statement)
+	assert.Equal(100, getUsers.Count)
+	assert.Zero(getUsers.Errors)
+	assert.Equal(200, getUsers.Rows)
+	assert.Equal(5050*time.Millisecond, getUsers.Total)
+	assert.Equal(50500*time.Microsecond, getUsers.Mean)
+	assert.Equal(100*time.Millisecond, getUsers.Max)
+	assert.True(getUsers.P50 >= 50*time.Millisecond && getUsers.P50 <= 51*time.Millisecond)
+	assert.True(getUsers.P95 >= 95*time.Millisecond && getUsers.P95 <= 96*time.Millisecond)
+	assert.True(getUsers.P99 >= 99*time.Millisecond && getUsers.P99 <= 100*time.Millisecond)
+
+	deleteUsers := snapshot.Statements[1]
+	assert.Equal("delete_users", deleteUsers.Statement)
+	assert.Equal(1, deleteUsers.Count)
+	assert.Equal(1, deleteUsers.Errors)
+
+	a.Reset()
+	assert.Empty(a.Snapshot().Statements)
+}
+
+func TestAggregatorUnlabeled(t *testing.T) {
+	assert := assert.New(t)
+
+	a := New(OptRedact(RedactLiterals))
+	a.Add(context.Background(), db.NewQueryEvent("select * from users where id = 1", time.Millisecond))
+	a.Add(context.Background(), db.NewQueryEvent("select * from users\n\twhere id = 2", time.Millisecond))
+
+	snapshot := a.Snapshot()
+	assert.Len(snapshot.Statements, 1)
+	assert.Equal("select * from users where id = ?", snapshot.Statements[0].Statement)
+	assert.Equal(2, snapshot.Statements[0].Count)
+}
+
+func TestAggregatorSampleSize(t *testing.T) {
+	assert := assert.New(t)
+
+	a := New(OptSampleSize(10))
+	for index := 1; index <= 100; index++ {
+		a.Add(context.Background(), db.NewQueryEvent("", time.Duration(index)*time.Millisecond, db.OptQueryLabel("test")))
+	}
+	stats := a.Snapshot().Statements[0]
+	assert.Equal(100, stats.Count)
+	assert.True(stats.P50 > 90*time.Millisecond, "percentiles should be computed from the most recent samples")
+
+	a = New(OptSampleSize(-1))
+	assert.Zero(a.SampleSize)
+	a.Add(context.Background(), db.NewQueryEvent("", time.Millisecond, db.OptQueryLabel("test")))
+	assert.Equal(1, a.Snapshot().Statements[0].Count)
+}
+
+func TestAggregatorWindow(t *testing.T) {
+	assert := assert.New(t)
+
+	a := New(OptWindow(time.Millisecond))
+	a.Add(context.Background(), db.NewQueryEvent("", time.Millisecond, db.OptQueryLabel("test")))
+	a.since = a.since.Add(-time.Second)
+	assert.Empty(a.Snapshot().Statements)
+}
+
+func TestAggregatorSlowQuery(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	log := logger.MustNew(logger.OptOutput(buffer), logger.OptEnabled(db.SlowQueryFlag))
+	defer log.Close()
+
+	slow := make(chan db.SlowQueryEvent, 1)
+	log.Listen(db.SlowQueryFlag, "test", db.NewSlowQueryEventListener(func(_ context.Context, sqe db.SlowQueryEvent) {
+		slow <- sqe
+	}))
+
+	a := New(OptLog(log), OptSlowThreshold(time.Second), OptRedact(RedactLiterals))
+	a.Add(context.Background(), db.NewQueryEvent("select 'secret'", time.Millisecond, db.OptQueryLabel("fast")))
+	a.Add(context.Background(), db.NewQueryEvent("select 'secret'", 2*time.Second, db.OptQueryLabel("slow")))
+
+	sqe := <-slow
+	assert.Equal("slow", sqe.Label)
+	assert.Equal("select ?", sqe.Body)
+	assert.Equal(time.Second, sqe.Threshold)
+	assert.Len(a.Snapshot().Statements, 2)
+	assert.Contains(buffer.String(), "select ?")
+	assert.NotContains(buffer.String(), "secret")
+}
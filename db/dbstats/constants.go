@@ -0,0 +1,7 @@
+package dbstats
+
+// ListenerNameStats is the logger listener name for the aggregator.
+const ListenerNameStats = "dbstats"
+
+// DefaultSampleSize is the default number of recent latencies kept per statement.
+const DefaultSampleSize = 1024
@@ -0,0 +1,12 @@
+package dbstats
+
+import "github.com/blend/go-sdk/web"
+
+// Handler returns a web action that renders a snapshot of an aggregator's statistics as json.
+//
+//	app.GET("/debug/db/stats", dbstats.Handler(aggregator))
+func Handler(aggregator *Aggregator) web.Action {
+	return func(_ *web.Ctx) web.Result {
+		return web.JSON.Result(aggregator.Snapshot())
+	}
+}
@@ -0,0 +1,30 @@
+package dbstats
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/web"
+	"github.com/blend/go-sdk/webutil"
+)
+
+func TestHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	a := New()
+	a.Add(context.Background(), db.NewQueryEvent("select 1", time.Millisecond, db.OptQueryLabel("test")))
+
+	ctx := web.MockCtx(http.MethodGet, "/debug/db/stats")
+	result := Handler(a)(ctx)
+	assert.Nil(result.Render(ctx))
+
+	var snapshot Snapshot
+	assert.Nil(json.Unmarshal(ctx.Response.(*webutil.MockResponseWriter).Bytes(), &snapshot))
+	assert.Len(snapshot.Statements, 1)
+	assert.Equal("test", snapshot.Statements[0].Statement)
+}
@@ -0,0 +1,18 @@
+package dbstats
+
+import (
+	"context"
+
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/logger"
+)
+
+// AddListeners adds a listener for query events that adds them to an aggregator.
+func AddListeners(log logger.Listenable, aggregator *Aggregator) {
+	if log == nil || aggregator == nil {
+		return
+	}
+	log.Listen(db.QueryFlag, ListenerNameStats, db.NewQueryEventListener(func(ctx context.Context, qe db.QueryEvent) {
+		aggregator.Add(ctx, qe)
+	}))
+}
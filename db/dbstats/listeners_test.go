@@ -0,0 +1,35 @@
+package dbstats
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/logger"
+)
+
+func TestAddListeners(t *testing.T) {
+	assert := assert.New(t)
+
+	log := logger.MustNew(logger.OptOutput(new(bytes.Buffer)), logger.OptEnabled(db.QueryFlag))
+	defer log.Close()
+	a := New()
+	AddListeners(nil, a)
+	AddListeners(log, nil)
+	assert.False(log.HasListener(db.QueryFlag, ListenerNameStats))
+
+	AddListeners(log, a)
+	assert.True(log.HasListener(db.QueryFlag, ListenerNameStats))
+
+	log.Trigger(context.Background(), db.NewQueryEvent("select 1", time.Millisecond, db.OptQueryLabel("test")))
+
+	// listeners are called asynchronously.
+	deadline := time.Now().Add(time.Second)
+	for len(a.Snapshot().Statements) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Len(a.Snapshot().Statements, 1)
+}
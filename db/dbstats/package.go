@@ -0,0 +1,2 @@
+// Package dbstats aggregates per statement statistics, and logs slow queries, from `db.QueryEvent`s.
+package dbstats
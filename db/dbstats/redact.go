@@ -0,0 +1,55 @@
+package dbstats
+
+import "strings"
+
+// RedactLiterals replaces string and numeric literals in a statement with `?`.
+//
+// Positional parameters, e.g. `$1`, and quoted identifiers are left as is.
+func RedactLiterals(statement string) string {
+	var output strings.Builder
+	for index := 0; index < len(statement); index++ {
+		c := statement[index]
+		switch {
+		case c == '\'':
+			// a doubled quote, i.e. an escaped quote, is read as part of the literal.
+			end := index + 1
+			for end < len(statement) {
+				if statement[end] == '\'' {
+					if end+1 < len(statement) && statement[end+1] == '\'' {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			output.WriteByte('?')
+			index = end
+		case c == '"':
+			end := index + 1 + strings.IndexByte(statement[index+1:], '"')
+			if end <= index {
+				end = len(statement) - 1
+			}
+			output.WriteString(statement[index : end+1])
+			index = end
+		case isDigit(c) && (index == 0 || !isIdentifierPart(statement[index-1])):
+			end := index
+			for end < len(statement) && (isDigit(statement[end]) || statement[end] == '.') {
+				end++
+			}
+			output.WriteByte('?')
+			index = end - 1
+		default:
+			output.WriteByte(c)
+		}
+	}
+	return output.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentifierPart(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
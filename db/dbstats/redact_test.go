@@ -0,0 +1,17 @@
+package dbstats
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestRedactLiterals(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("select * from users where id = $1", RedactLiterals("select * from users where id = $1"))
+	assert.Equal("select * from users where email = ? and age > ?", RedactLiterals("select * from users where email = 'foo@bar.com' and age > 21"))
+	assert.Equal("select ? as \"name 1\"", RedactLiterals("select 'it''s' as \"name 1\""))
+	assert.Equal("select ? from table_2", RedactLiterals("select 3.14 from table_2"))
+	assert.Equal("select ?", RedactLiterals("select 'unterminated"))
+}
@@ -0,0 +1,77 @@
+package dbtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/uuid"
+)
+
+// HasEnv returns if the database is configured in the environment, that is
+// if `DATABASE_URL` or any of the `DB_*` environment variables are set.
+func HasEnv() bool {
+	vars := env.Env()
+	if vars.Has("DATABASE_URL") {
+		return true
+	}
+	for key := range vars {
+		if strings.HasPrefix(key, "DB_") {
+			return true
+		}
+	}
+	return false
+}
+
+// Connection returns an open connection configured from the environment.
+// It skips the test if the database is not configured, and fails it if the connection can't be opened.
+// The connection should be closed when the test completes.
+func Connection(t testing.TB, options ...db.Option) *db.Connection {
+	t.Helper()
+	if !HasEnv() {
+		t.Skip("dbtest: the database is not configured; set DATABASE_URL or DB_* environment variables")
+	}
+	conn, err := db.Open(db.New(append([]db.Option{db.OptConfigFromEnv()}, options...)...))
+	if err != nil {
+		t.Fatalf("dbtest: %+v", err)
+	}
+	return conn
+}
+
+// Schema creates a schema with a random name, and returns an open connection that uses it, and a func
+// that drops it and closes the connection.
+//
+// Use it for tests that commit, or otherwise can't run in a transaction, e.g. migrations;
+// otherwise prefer `Tx.CreateSchema`.
+//
+//	conn, drop := dbtest.Schema(t, dbtest.Connection(t))
+//	defer drop()
+func Schema(t testing.TB, conn *db.Connection) (*db.Connection, func()) {
+	t.Helper()
+	schema := SchemaName()
+	if _, err := conn.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatalf("dbtest: %+v", err)
+	}
+	cfg := conn.Config
+	cfg.Schema = schema
+	schemaConn, err := db.Open(db.New(db.OptConfig(cfg)))
+	if err != nil {
+		_, _ = conn.Exec("DROP SCHEMA " + schema + " CASCADE")
+		t.Fatalf("dbtest: %+v", err)
+	}
+	return schemaConn, func() {
+		t.Helper()
+		if err := schemaConn.Close(); err != nil {
+			t.Errorf("dbtest: %+v", err)
+		}
+		if _, err := conn.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			t.Errorf("dbtest: %+v", err)
+		}
+	}
+}
+
+// SchemaName returns a random schema name.
+func SchemaName() string {
+	return "dbtest_" + uuid.V4().String()
+}
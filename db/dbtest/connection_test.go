@@ -0,0 +1,42 @@
+package dbtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/env"
+)
+
+func TestHasEnv(t *testing.T) {
+	assert := assert.New(t)
+	defer env.Restore()
+
+	env.SetEnv(env.Vars{"HOME": "/root"})
+	assert.False(HasEnv())
+
+	env.SetEnv(env.Vars{"DB_HOST": "localhost"})
+	assert.True(HasEnv())
+
+	env.SetEnv(env.Vars{"DATABASE_URL": "postgres://localhost/postgres"})
+	assert.True(HasEnv())
+}
+
+func TestConnectionSkips(t *testing.T) {
+	defer env.Restore()
+	env.SetEnv(env.Vars{})
+
+	var skipped bool
+	t.Run("skips", func(t *testing.T) {
+		defer func() { skipped = t.Skipped() }()
+		Connection(t)
+		t.Error("should have skipped")
+	})
+	assert.New(t).True(skipped)
+}
+
+func TestSchemaName(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(strings.HasPrefix(SchemaName(), "dbtest_"))
+	assert.NotEqual(SchemaName(), SchemaName())
+}
@@ -0,0 +1,16 @@
+package dbtest
+
+import (
+	"time"
+)
+
+type testUser struct {
+	ID         int               `db:"id,pk"`
+	Email      string            `db:"email"`
+	CreatedUTC time.Time         `db:"created_utc"`
+	Settings   map[string]string `db:"settings,json"`
+}
+
+func (testUser) TableName() string { return "test_users" }
+
+const createTestUsers = `CREATE TABLE test_users (id int primary key, email text not null, created_utc timestamp not null, settings json)`
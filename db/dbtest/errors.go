@@ -0,0 +1,12 @@
+package dbtest
+
+import "github.com/blend/go-sdk/ex"
+
+const (
+	// ErrFixtureExtension is an error indicating a fixture file has an unsupported extension.
+	ErrFixtureExtension ex.Class = "dbtest: invalid fixture extension; expected .json, .yaml or .yml"
+	// ErrFixtureCollection is an error indicating a fixture collection is not a reference to a slice of structs.
+	ErrFixtureCollection ex.Class = "dbtest: fixture collection must be a reference to a slice of structs"
+	// ErrFixtureColumn is an error indicating a fixture row has a column the struct does not map.
+	ErrFixtureColumn ex.Class = "dbtest: fixture column is not mapped"
+)
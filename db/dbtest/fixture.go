@@ -0,0 +1,127 @@
+package dbtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/yaml"
+)
+
+// LoadFixture reads the rows of a fixture file into a collection, and creates them with `CreateMany`.
+//
+// See `ReadFixture` for the file format.
+func LoadFixture(invocation *db.Invocation, path string, collection interface{}) error {
+	if err := ReadFixture(path, collection); err != nil {
+		return err
+	}
+	return invocation.CreateMany(collection)
+}
+
+// ReadFixture reads the rows of a fixture file into a collection, which must be a reference to a slice
+// of structs (or of references to structs) mapped with `db` tags.
+//
+// The file is a json (`.json`) or yaml (`.yaml` or `.yml`) list of rows, each keyed by column name:
+//
+//   - id: 1
+//     email: foo@example.com
+//     created_utc: 2020-01-01T00:00:00Z
+//
+// Values are converted to the column's field type through json, so for example times are
+// RFC3339 strings, and json columns can be nested objects.
+func ReadFixture(path string, collection interface{}) error {
+	var unmarshal func([]byte, interface{}) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		unmarshal = json.Unmarshal
+	case ".yaml", ".yml":
+		unmarshal = yaml.Unmarshal
+	default:
+		return ex.New(ErrFixtureExtension, ex.OptMessagef("path: %s", path))
+	}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ex.New(err, ex.OptMessagef("path: %s", path))
+	}
+	var rows []map[string]interface{}
+	if err = unmarshal(contents, &rows); err != nil {
+		return ex.New(err, ex.OptMessagef("path: %s", path))
+	}
+	if err = fixtureRows(rows, collection); err != nil {
+		return ex.New(err, ex.OptMessagef("path: %s", path))
+	}
+	return nil
+}
+
+// fixtureRows sets a collection to new instances of its element type populated from rows.
+func fixtureRows(rows []map[string]interface{}, collection interface{}) error {
+	collectionValue := reflect.ValueOf(collection)
+	if collectionValue.Kind() != reflect.Ptr || collectionValue.Elem().Kind() != reflect.Slice {
+		return ex.New(ErrFixtureCollection, ex.OptMessagef("type: %T", collection))
+	}
+	sliceValue := collectionValue.Elem()
+	elemType := sliceValue.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return ex.New(ErrFixtureCollection, ex.OptMessagef("type: %T", collection))
+	}
+
+	columns := db.CachedColumnCollectionFromInstance(reflect.New(elemType).Interface()).Lookup()
+	output := reflect.MakeSlice(sliceValue.Type(), 0, len(rows))
+	for index, row := range rows {
+		elem := reflect.New(elemType)
+		for name, value := range row {
+			column, ok := columns[name]
+			if !ok {
+				return ex.New(ErrFixtureColumn, ex.OptMessagef("row: %d, column: %s", index, name))
+			}
+			if err := setFixtureValue(elem.Elem().FieldByName(column.FieldName), value); err != nil {
+				return ex.New(err, ex.OptMessagef("row: %d, column: %s", index, name))
+			}
+		}
+		if isPtr {
+			output = reflect.Append(output, elem)
+		} else {
+			output = reflect.Append(output, elem.Elem())
+		}
+	}
+	sliceValue.Set(output)
+	return nil
+}
+
+// setFixtureValue sets a field to a value, converting it to the field type through json.
+func setFixtureValue(field reflect.Value, value interface{}) error {
+	contents, err := json.Marshal(jsonValue(value))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(contents, field.Addr().Interface())
+}
+
+// jsonValue converts the maps yaml decodes nested objects to into maps json can encode.
+func jsonValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		output := make(map[string]interface{}, len(typed))
+		for key, elem := range typed {
+			output[fmt.Sprint(key)] = jsonValue(elem)
+		}
+		return output
+	case []interface{}:
+		output := make([]interface{}, len(typed))
+		for index, elem := range typed {
+			output[index] = jsonValue(elem)
+		}
+		return output
+	default:
+		return value
+	}
+}
@@ -0,0 +1,45 @@
+package dbtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestReadFixture(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, path := range []string{"testdata/users.yml", "testdata/users.json"} {
+		var users []testUser
+		assert.Nil(ReadFixture(path, &users), path)
+		assert.Len(users, 2, path)
+		assert.Equal(1, users[0].ID)
+		assert.Equal("foo@example.com", users[0].Email)
+		assert.Equal(time.Date(2020, 01, 01, 0, 0, 0, 0, time.UTC), users[0].CreatedUTC.UTC())
+		assert.Equal(map[string]string{"theme": "dark"}, users[0].Settings)
+		assert.Equal(2, users[1].ID)
+		assert.Nil(users[1].Settings)
+	}
+}
+
+func TestReadFixtureReferences(t *testing.T) {
+	assert := assert.New(t)
+
+	var users []*testUser
+	assert.Nil(ReadFixture("testdata/users.yml", &users))
+	assert.Len(users, 2)
+	assert.Equal("bar@example.com", users[1].Email)
+}
+
+func TestReadFixtureErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	var users []testUser
+	assert.True(ex.Is(ReadFixture("testdata/users.txt", &users), ErrFixtureExtension))
+	assert.True(ex.Is(ReadFixture("testdata/users.yml", users), ErrFixtureCollection))
+	assert.True(ex.Is(ReadFixture("testdata/users.yml", &[]string{}), ErrFixtureCollection))
+	assert.True(ex.Is(ReadFixture("testdata/unmapped.yml", &users), ErrFixtureColumn))
+	assert.NotNil(ReadFixture("testdata/missing.yml", &users))
+}
@@ -0,0 +1,27 @@
+/*
+Package dbtest provides helpers for tests against a postgres database.
+
+Each test runs in a transaction that is always rolled back, and subtests can run in savepoints
+within it that are rolled back in turn:
+
+	func TestUsers(t *testing.T) {
+		conn := dbtest.Connection(t)
+		defer conn.Close()
+
+		dbtest.Run(t, conn, func(tx *dbtest.Tx) {
+			tx.CreateSchema()
+			tx.Exec("CREATE TABLE users (id int primary key, email text)")
+			tx.Fixture("testdata/users.yml", &[]User{})
+			tx.AssertCount(3, "users")
+
+			tx.Run("delete", func(tx *dbtest.Tx) {
+				tx.Exec("DELETE FROM users")
+				tx.AssertCount(0, "users")
+			})
+			tx.AssertCount(3, "users")
+		})
+	}
+
+The connection is configured from the `DB_*` environment variables, and tests are skipped if none are set.
+*/
+package dbtest // import "github.com/blend/go-sdk/db/dbtest"
@@ -0,0 +1,160 @@
+package dbtest
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/blend/go-sdk/db"
+)
+
+// Run runs an action in a transaction that is rolled back when it returns, or panics.
+func Run(t testing.TB, conn *db.Connection, action func(*Tx)) {
+	t.Helper()
+	tx := Begin(t, conn)
+	defer tx.Rollback()
+	action(tx)
+}
+
+// Begin begins a transaction, failing the test if it can't be begun.
+// The transaction must be rolled back with `Rollback`.
+func Begin(t testing.TB, conn *db.Connection) *Tx {
+	t.Helper()
+	tx, err := conn.Begin()
+	if err != nil {
+		t.Fatalf("dbtest: %+v", err)
+	}
+	return &Tx{T: t, Conn: conn, Tx: tx}
+}
+
+// Tx is a transaction for a test.
+//
+// Its helpers fail the test on error, rather than returning it.
+type Tx struct {
+	T    testing.TB
+	Conn *db.Connection
+	Tx   *sql.Tx
+
+	savepoint  string
+	savepoints int
+}
+
+// Invoke returns an invocation in the transaction.
+func (tx *Tx) Invoke(options ...db.InvocationOption) *db.Invocation {
+	return tx.Conn.Invoke(append([]db.InvocationOption{db.OptTx(tx.Tx)}, options...)...)
+}
+
+// Exec executes a statement in the transaction.
+func (tx *Tx) Exec(statement string, args ...interface{}) {
+	tx.T.Helper()
+	if err := db.IgnoreExecResult(tx.Invoke().Exec(statement, args...)); err != nil {
+		tx.T.Fatalf("dbtest: %+v", err)
+	}
+}
+
+// Run runs an action as a subtest, in a savepoint that is rolled back when it returns, or panics.
+//
+// The subtests of a transaction share its connection, and so can't be run in parallel.
+func (tx *Tx) Run(name string, action func(*Tx)) bool {
+	tx.T.Helper()
+	t, ok := tx.T.(*testing.T)
+	if !ok {
+		tx.T.Fatalf("dbtest: subtests require a *testing.T")
+	}
+	return t.Run(name, func(t *testing.T) {
+		savepoint := tx.Savepoint(t)
+		defer savepoint.Rollback()
+		action(savepoint)
+	})
+}
+
+// Savepoint creates a savepoint in the transaction, returning a transaction for a test that
+// rolls back to the savepoint on `Rollback`.
+func (tx *Tx) Savepoint(t testing.TB) *Tx {
+	t.Helper()
+	tx.savepoints++
+	savepoint := &Tx{T: t, Conn: tx.Conn, Tx: tx.Tx, savepoint: fmt.Sprintf("%s_%d", tx.savepointPrefix(), tx.savepoints)}
+	if _, err := tx.Tx.Exec("SAVEPOINT " + savepoint.savepoint); err != nil {
+		t.Fatalf("dbtest: %+v", err)
+	}
+	return savepoint
+}
+
+// Rollback rolls back the transaction, or to the savepoint.
+func (tx *Tx) Rollback() {
+	tx.T.Helper()
+	if tx.savepoint != "" {
+		if _, err := tx.Tx.Exec("ROLLBACK TO SAVEPOINT " + tx.savepoint); err != nil {
+			tx.T.Errorf("dbtest: %+v", err)
+		}
+		return
+	}
+	if err := tx.Tx.Rollback(); err != nil && err != sql.ErrTxDone {
+		tx.T.Errorf("dbtest: %+v", err)
+	}
+}
+
+// CreateSchema creates a schema with a random name in the transaction, and sets the search path
+// to it for the rest of the transaction. It is dropped when the transaction is rolled back.
+func (tx *Tx) CreateSchema() string {
+	tx.T.Helper()
+	schema := SchemaName()
+	tx.Exec("CREATE SCHEMA " + schema)
+	tx.Exec("SET LOCAL search_path TO " + schema)
+	return schema
+}
+
+// Fixture reads the rows of a fixture file into a collection and creates them in the transaction.
+//
+// See `LoadFixture` for the file format.
+func (tx *Tx) Fixture(path string, collection interface{}) {
+	tx.T.Helper()
+	if err := LoadFixture(tx.Invoke(), path, collection); err != nil {
+		tx.T.Fatalf("dbtest: %+v", err)
+	}
+}
+
+// Count returns the number of rows in a table.
+func (tx *Tx) Count(table string) int {
+	tx.T.Helper()
+	return tx.CountWhere(table, "")
+}
+
+// CountWhere returns the number of rows in a table that match a where clause, e.g. `email = $1`.
+func (tx *Tx) CountWhere(table, where string, args ...interface{}) int {
+	tx.T.Helper()
+	statement := "SELECT count(*) FROM " + table
+	if where != "" {
+		statement = statement + " WHERE " + where
+	}
+	var count int
+	if _, err := tx.Invoke().Query(statement, args...).Scan(&count); err != nil {
+		tx.T.Fatalf("dbtest: %+v", err)
+	}
+	return count
+}
+
+// AssertCount asserts the number of rows in a table.
+func (tx *Tx) AssertCount(expected int, table string) {
+	tx.T.Helper()
+	if actual := tx.Count(table); actual != expected {
+		tx.T.Fatalf("dbtest: expected %d rows in %s, actual: %d", expected, table, actual)
+	}
+}
+
+// AssertCountWhere asserts the number of rows in a table that match a where clause.
+//
+//	tx.AssertCountWhere(1, "users", "email = $1", "foo@example.com")
+func (tx *Tx) AssertCountWhere(expected int, table, where string, args ...interface{}) {
+	tx.T.Helper()
+	if actual := tx.CountWhere(table, where, args...); actual != expected {
+		tx.T.Fatalf("dbtest: expected %d rows in %s where %s, actual: %d", expected, table, where, actual)
+	}
+}
+
+func (tx *Tx) savepointPrefix() string {
+	if tx.savepoint != "" {
+		return tx.savepoint
+	}
+	return "dbtest"
+}
@@ -0,0 +1,65 @@
+package dbtest
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/db"
+)
+
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+	conn := Connection(t)
+	defer conn.Close()
+
+	var schema string
+	Run(t, conn, func(tx *Tx) {
+		schema = tx.CreateSchema()
+		tx.Exec(createTestUsers)
+		tx.Fixture("testdata/users.yml", &[]testUser{})
+		tx.AssertCount(2, "test_users")
+		tx.AssertCountWhere(1, "test_users", "email = $1", "foo@example.com")
+
+		var user testUser
+		found, err := tx.Invoke().Get(&user, 1)
+		assert.Nil(err)
+		assert.True(found)
+		assert.Equal("dark", user.Settings["theme"])
+
+		tx.Run("savepoint", func(tx *Tx) {
+			tx.Exec("DELETE FROM test_users WHERE id = $1", 1)
+			tx.AssertCount(1, "test_users")
+
+			tx.Run("nested", func(tx *Tx) {
+				tx.Exec("DELETE FROM test_users")
+				tx.AssertCount(0, "test_users")
+			})
+			tx.AssertCount(1, "test_users")
+		})
+		tx.AssertCount(2, "test_users")
+	})
+
+	var exists bool
+	_, err := conn.Query("SELECT EXISTS (SELECT 1 FROM information_schema.schemata WHERE schema_name = $1)", schema).Scan(&exists)
+	assert.Nil(err)
+	assert.False(exists)
+}
+
+func TestSchema(t *testing.T) {
+	assert := assert.New(t)
+	conn := Connection(t)
+	defer conn.Close()
+
+	schemaConn, drop := Schema(t, conn)
+	assert.Nil(db.IgnoreExecResult(schemaConn.Exec(createTestUsers)))
+	assert.Nil(LoadFixture(schemaConn.Invoke(), "testdata/users.json", &[]testUser{}))
+
+	var count int
+	_, err := schemaConn.Query("SELECT count(*) FROM test_users").Scan(&count)
+	assert.Nil(err)
+	assert.Equal(2, count)
+
+	drop()
+	_, err = conn.Query("SELECT count(*) FROM " + schemaConn.Config.Schema + ".test_users").Scan(&count)
+	assert.NotNil(err)
+}
@@ -0,0 +1,47 @@
+package db
+
+var (
+	_ Builder = (*DeleteBuilder)(nil)
+)
+
+// Delete returns a delete builder for the table an object is mapped to.
+func Delete(object DatabaseMapped) *DeleteBuilder {
+	return &DeleteBuilder{
+		table:   TableName(object),
+		columns: CachedColumnCollectionFromInstance(object),
+	}
+}
+
+// DeleteBuilder builds a delete statement.
+type DeleteBuilder struct {
+	table      string
+	columns    *ColumnCollection
+	predicates []Predicate
+	returning  []string
+}
+
+// Where adds predicates to the where clause; multiple predicates are joined with `AND`.
+func (d *DeleteBuilder) Where(predicates ...Predicate) *DeleteBuilder {
+	d.predicates = append(d.predicates, predicates...)
+	return d
+}
+
+// Returning sets the columns returned by the statement.
+func (d *DeleteBuilder) Returning(columns ...string) *DeleteBuilder {
+	d.returning = append(d.returning, columns...)
+	return d
+}
+
+// Label implements Builder.
+func (d *DeleteBuilder) Label() string {
+	return d.table + "_delete_where"
+}
+
+// Build implements Builder.
+func (d *DeleteBuilder) Build() (string, []interface{}, error) {
+	s := NewStatement(d.table, d.columns)
+	s.WriteString("DELETE FROM " + d.table)
+	s.WriteWhere(d.predicates)
+	s.WriteReturning(d.returning)
+	return s.Build()
+}
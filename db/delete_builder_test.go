@@ -0,0 +1,21 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestDeleteBuilder(t *testing.T) {
+	assert := assert.New(t)
+
+	d := Delete(builderTestObj{})
+	assert.Equal("builder_test_delete_where", d.Label())
+	statement, args, err := d.Where(In("id", 1, 2)).Returning("id").Build()
+	assert.Nil(err)
+	assert.Equal("DELETE FROM builder_test WHERE id IN ($1,$2) RETURNING id", statement)
+	assert.Equal([]interface{}{1, 2}, args)
+
+	_, _, err = Delete(builderTestObj{}).Where(Eq("bogus", 1)).Build()
+	assert.True(IsColumnNotFound(err))
+}
@@ -0,0 +1,218 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"reflect"
+
+	"github.com/blend/go-sdk/crypto"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/secrets"
+)
+
+// ColumnEncrypter encrypts and decrypts the values of encrypted columns.
+//
+// Columns are encrypted if they are tagged `db:"...,encrypted"`; their fields must be strings or byte slices,
+// or references to them, and their values are encrypted by `Create`, `CreateIfNotExists`, `CreateMany`,
+// `Update` and `Upsert`, and decrypted by `Get`, `All`, `Out`, `OutMany` and cursors. The columns should be `bytea`.
+//
+// Ciphertexts should be prefixed with the version of the key they were encrypted with, so that keys can be
+// rotated while rows encrypted with previous keys can still be decrypted.
+type ColumnEncrypter interface {
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// Assert the encrypters implement the interface.
+var (
+	_ ColumnEncrypter = (*LocalTransitEncrypter)(nil)
+	_ ColumnEncrypter = (*TransitEncrypter)(nil)
+)
+
+// NewLocalTransitEncrypter returns a column encrypter that uses a local transit.
+func NewLocalTransitEncrypter(transit crypto.LocalTransit) LocalTransitEncrypter {
+	return LocalTransitEncrypter{Transit: transit}
+}
+
+// LocalTransitEncrypter is a column encrypter that uses a local transit.
+//
+// The local transit prefixes ciphertexts with the key version its context provider returns, and
+// decrypts them with the key its key provider returns for that version.
+type LocalTransitEncrypter struct {
+	Transit crypto.LocalTransit
+}
+
+// Encrypt implements ColumnEncrypter.
+func (lte LocalTransitEncrypter) Encrypt(_ context.Context, plaintext []byte) ([]byte, error) {
+	ciphertext := new(bytes.Buffer)
+	if err := lte.Transit.Encrypt(ciphertext, bytes.NewReader(plaintext)); err != nil {
+		return nil, err
+	}
+	return ciphertext.Bytes(), nil
+}
+
+// Decrypt implements ColumnEncrypter.
+func (lte LocalTransitEncrypter) Decrypt(_ context.Context, ciphertext []byte) ([]byte, error) {
+	plaintext := new(bytes.Buffer)
+	if err := lte.Transit.Decrypt(plaintext, bytes.NewReader(ciphertext)); err != nil {
+		return nil, err
+	}
+	return plaintext.Bytes(), nil
+}
+
+// NewTransitEncrypter returns a column encrypter that uses a transit client and key.
+func NewTransitEncrypter(client secrets.TransitClient, key string) TransitEncrypter {
+	return TransitEncrypter{Client: client, Key: key}
+}
+
+// TransitEncrypter is a column encrypter that uses a transit client and key.
+//
+// Transit ciphertexts are prefixed with the version of the key, e.g. `vault:v2:`, and keys are rotated
+// with the transit service.
+type TransitEncrypter struct {
+	Client secrets.TransitClient
+	Key    string
+}
+
+// Encrypt implements ColumnEncrypter.
+func (te TransitEncrypter) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	ciphertext, err := te.Client.Encrypt(ctx, te.Key, nil, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(ciphertext), nil
+}
+
+// Decrypt implements ColumnEncrypter.
+func (te TransitEncrypter) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return te.Client.Decrypt(ctx, te.Key, nil, string(ciphertext))
+}
+
+// BlindIndex returns the blind index of a value of an encrypted column, for equality lookups, e.g.
+//
+//	index, err := conn.Invoke().BlindIndex(ssn)
+//	...
+//	found, err := conn.Invoke().Query("SELECT * FROM users WHERE ssn_index = $1", index).Out(&user)
+//
+// Blind index columns are tagged `db:"...,blindindex=<encrypted column>"`, and their values are the hex encoded
+// HMAC-SHA256 of the encrypted column's plaintext, with the invocation's blind index key, computed on writes.
+// The columns should be `text`, and their fields strings.
+func (i *Invocation) BlindIndex(value interface{}) (string, error) {
+	plaintext, ok, err := columnBytes(value)
+	if err != nil || !ok {
+		return "", err
+	}
+	if len(i.BlindIndexKey) == 0 {
+		return "", Error(ErrBlindIndexKeyUnset)
+	}
+	return hex.EncodeToString(crypto.HMAC256(i.BlindIndexKey, plaintext)), nil
+}
+
+// --------------------------------------------------------------------------------
+// helpers
+// --------------------------------------------------------------------------------
+
+// writeValues returns the values of columns of an object for a write, with the values of encrypted
+// columns encrypted, and the values of blind index columns computed from their encrypted columns.
+func (i *Invocation) writeValues(cols *ColumnCollection, object interface{}) ([]interface{}, error) {
+	values := cols.ColumnValues(object)
+	var lookup map[string]*Column
+	for index, col := range cols.Columns() {
+		if col.IsEncrypted {
+			plaintext, ok, err := columnBytes(values[index])
+			if err != nil {
+				return nil, Error(err, ex.OptMessagef("column: %s", col.ColumnName))
+			}
+			if !ok {
+				values[index] = nil
+				continue
+			}
+			if i.Encrypter == nil {
+				return nil, Error(ErrEncrypterUnset, ex.OptMessagef("column: %s", col.ColumnName))
+			}
+			if values[index], err = i.Encrypter.Encrypt(i.Context, plaintext); err != nil {
+				return nil, Error(err)
+			}
+		} else if col.BlindIndexOf != "" {
+			if lookup == nil {
+				lookup = CachedColumnCollectionFromInstance(object).Lookup()
+			}
+			source, ok := lookup[col.BlindIndexOf]
+			if !ok || !source.IsEncrypted {
+				return nil, Error(ErrEncryptedColumnInvalid, ex.OptMessagef("column: %s, blind index of: %s", col.ColumnName, col.BlindIndexOf))
+			}
+			blindIndex, err := i.BlindIndex(source.GetValue(object))
+			if err != nil {
+				return nil, Error(err, ex.OptMessagef("column: %s", col.ColumnName))
+			}
+			if blindIndex == "" {
+				values[index] = nil
+			} else {
+				values[index] = blindIndex
+			}
+		}
+	}
+	return values, nil
+}
+
+// decryptColumns decrypts the values of the encrypted columns of an object read from the database.
+func (i *Invocation) decryptColumns(object interface{}) error {
+	objectValue := ReflectValue(object)
+	for _, col := range CachedColumnCollectionFromInstance(object).Columns() {
+		if !col.IsEncrypted {
+			continue
+		}
+		ciphertext, ok, err := columnBytes(col.GetValue(object))
+		if err != nil {
+			return Error(err, ex.OptMessagef("column: %s", col.ColumnName))
+		}
+		if !ok || len(ciphertext) == 0 {
+			continue
+		}
+		if i.Encrypter == nil {
+			return Error(ErrEncrypterUnset, ex.OptMessagef("column: %s", col.ColumnName))
+		}
+		plaintext, err := i.Encrypter.Decrypt(i.Context, ciphertext)
+		if err != nil {
+			return Error(err, ex.OptMessagef("column: %s", col.ColumnName))
+		}
+		setColumnBytes(objectValue.FieldByName(col.FieldName), plaintext)
+	}
+	return nil
+}
+
+// columnBytes returns the contents of the value of an encrypted column, and false if it's null.
+func columnBytes(value interface{}) ([]byte, bool, error) {
+	if reflected := reflect.ValueOf(value); reflected.Kind() == reflect.Ptr {
+		if reflected.IsNil() {
+			return nil, false, nil
+		}
+		value = reflected.Elem().Interface()
+	}
+	switch typed := value.(type) {
+	case nil:
+		return nil, false, nil
+	case string:
+		return []byte(typed), true, nil
+	case []byte:
+		return typed, typed != nil, nil
+	default:
+		return nil, false, Error(ErrEncryptedColumnInvalid, ex.OptMessagef("type: %T", value))
+	}
+}
+
+// setColumnBytes sets the field of an encrypted column, which is a string or byte slice, or a reference to one.
+func setColumnBytes(field reflect.Value, contents []byte) {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		field = field.Elem()
+	}
+	if field.Kind() == reflect.String {
+		field.SetString(string(contents))
+		return
+	}
+	field.SetBytes(contents)
+}
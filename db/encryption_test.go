@@ -0,0 +1,225 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/crypto"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/secrets"
+)
+
+type encryptionTest struct {
+	ID       int     `db:"id,pk"`
+	SSN      string  `db:"ssn,encrypted"`
+	SSNIndex string  `db:"ssn_index,blindindex=ssn"`
+	Notes    *string `db:"notes,encrypted"`
+	Name     string  `db:"name"`
+}
+
+func (encryptionTest) TableName() string { return "encryption_test" }
+
+type encryptionInvalidTest struct {
+	ID        int    `db:"id,pk"`
+	Name      string `db:"name"`
+	NameIndex string `db:"name_index,blindindex=name"`
+}
+
+type transitClientTest struct {
+	secrets.TransitClient
+}
+
+func (transitClientTest) Encrypt(_ context.Context, key string, _, data []byte) (string, error) {
+	return "vault:v1:" + key + ":" + string(data), nil
+}
+
+func (transitClientTest) Decrypt(_ context.Context, key string, _ []byte, ciphertext string) ([]byte, error) {
+	return []byte(strings.TrimPrefix(ciphertext, "vault:v1:"+key+":")), nil
+}
+
+func encryptionTestInvocation(options ...InvocationOption) *Invocation {
+	i := defaultDB().Invoke(options...)
+	i.Encrypter = NewLocalTransitEncrypter(crypto.NewLocalTransit(
+		localTransitVersion("20200101"),
+		crypto.OptLocalTransitKey(crypto.MustCreateKey(32)),
+	))
+	i.BlindIndexKey = crypto.MustCreateKey(32)
+	return i
+}
+
+func localTransitVersion(version string) crypto.LocalTransitOption {
+	return crypto.OptLocalTransitContextProvider(func() string { return version })
+}
+
+func TestNewColumnFromFieldTagEncrypted(t *testing.T) {
+	assert := assert.New(t)
+
+	field, _ := reflect.TypeOf(encryptionTest{}).FieldByName("SSN")
+	col := NewColumnFromFieldTag(field)
+	assert.True(col.IsEncrypted)
+	assert.Empty(col.BlindIndexOf)
+
+	field, _ = reflect.TypeOf(encryptionTest{}).FieldByName("SSNIndex")
+	col = NewColumnFromFieldTag(field)
+	assert.False(col.IsEncrypted)
+	assert.Equal("ssn", col.BlindIndexOf)
+
+	// the blind index column doesn't affect the other options.
+	field = reflect.StructField{Name: "Index", Type: reflect.TypeOf(""), Tag: `db:"pk_index,blindindex=pk_json,uk"`}
+	col = NewColumnFromFieldTag(field)
+	assert.Equal("pk_json", col.BlindIndexOf)
+	assert.True(col.IsUniqueKey)
+	assert.False(col.IsPrimaryKey)
+	assert.False(col.IsJSON)
+}
+
+func TestLocalTransitEncrypterRotation(t *testing.T) {
+	assert := assert.New(t)
+
+	keys := map[string][]byte{
+		"20200101": crypto.MustCreateKey(32),
+		"20210101": crypto.MustCreateKey(32),
+	}
+	keyProvider := crypto.OptLocalTransitKeyProvider(func(version string) ([]byte, error) {
+		key, ok := keys[version]
+		if !ok {
+			return nil, ex.New("key not found")
+		}
+		return key, nil
+	})
+
+	previous := NewLocalTransitEncrypter(crypto.NewLocalTransit(localTransitVersion("20200101"), keyProvider))
+	ciphertext, err := previous.Encrypt(context.Background(), []byte("123-45-6789"))
+	assert.Nil(err)
+	assert.True(strings.HasPrefix(string(ciphertext), "20200101:"))
+
+	current := NewLocalTransitEncrypter(crypto.NewLocalTransit(localTransitVersion("20210101"), keyProvider))
+	plaintext, err := current.Decrypt(context.Background(), ciphertext)
+	assert.Nil(err)
+	assert.Equal("123-45-6789", string(plaintext))
+
+	ciphertext, err = current.Encrypt(context.Background(), []byte("123-45-6789"))
+	assert.Nil(err)
+	assert.True(strings.HasPrefix(string(ciphertext), "20210101:"))
+}
+
+func TestTransitEncrypter(t *testing.T) {
+	assert := assert.New(t)
+
+	encrypter := NewTransitEncrypter(transitClientTest{}, "pii")
+	ciphertext, err := encrypter.Encrypt(context.Background(), []byte("123-45-6789"))
+	assert.Nil(err)
+	assert.Equal("vault:v1:pii:123-45-6789", string(ciphertext))
+
+	plaintext, err := encrypter.Decrypt(context.Background(), ciphertext)
+	assert.Nil(err)
+	assert.Equal("123-45-6789", string(plaintext))
+}
+
+func TestInvocationBlindIndex(t *testing.T) {
+	assert := assert.New(t)
+
+	i := encryptionTestInvocation()
+	index, err := i.BlindIndex("123-45-6789")
+	assert.Nil(err)
+	assert.Len(index, 64)
+	other, err := i.BlindIndex([]byte("123-45-6789"))
+	assert.Nil(err)
+	assert.Equal(index, other)
+
+	other, err = i.BlindIndex("987-65-4321")
+	assert.Nil(err)
+	assert.NotEqual(index, other)
+
+	empty, err := i.BlindIndex((*string)(nil))
+	assert.Nil(err)
+	assert.Empty(empty)
+
+	_, err = defaultDB().Invoke().BlindIndex("123-45-6789")
+	assert.True(ex.Is(err, ErrBlindIndexKeyUnset))
+	_, err = i.BlindIndex(123)
+	assert.True(ex.Is(err, ErrEncryptedColumnInvalid))
+}
+
+func TestInvocationWriteValuesDecryptColumns(t *testing.T) {
+	assert := assert.New(t)
+
+	i := encryptionTestInvocation()
+	object := encryptionTest{ID: 1, SSN: "123-45-6789", Name: "foo"}
+	cols := Columns(object)
+
+	values, err := i.writeValues(cols, &object)
+	assert.Nil(err)
+	assert.Equal(1, values[0])
+	ciphertext, ok := values[1].([]byte)
+	assert.True(ok)
+	assert.True(strings.HasPrefix(string(ciphertext), "20200101:"))
+	index, err := i.BlindIndex(object.SSN)
+	assert.Nil(err)
+	assert.Equal(index, values[2])
+	assert.Nil(values[3])
+	assert.Equal("foo", values[4])
+
+	// the object is unchanged.
+	assert.Equal("123-45-6789", object.SSN)
+
+	read := encryptionTest{ID: 1, SSN: string(ciphertext), SSNIndex: index, Name: "foo"}
+	assert.Nil(i.decryptColumns(&read))
+	assert.Equal("123-45-6789", read.SSN)
+	assert.Equal(index, read.SSNIndex)
+	assert.Nil(read.Notes)
+
+	_, err = defaultDB().Invoke().writeValues(cols, &object)
+	assert.True(ex.Is(err, ErrEncrypterUnset))
+	assert.True(ex.Is(defaultDB().Invoke().decryptColumns(&read), ErrEncrypterUnset))
+
+	_, err = i.writeValues(Columns(encryptionInvalidTest{}), &encryptionInvalidTest{Name: "foo"})
+	assert.True(ex.Is(err, ErrEncryptedColumnInvalid))
+}
+
+func TestInvocationEncryptedColumns(t *testing.T) {
+	assert := assert.New(t)
+	tx, err := defaultDB().Begin()
+	assert.Nil(err)
+	defer tx.Rollback()
+
+	i := encryptionTestInvocation()
+	invoke := func() *Invocation {
+		invocation := defaultDB().Invoke(OptTx(tx))
+		invocation.Encrypter, invocation.BlindIndexKey = i.Encrypter, i.BlindIndexKey
+		return invocation
+	}
+	assert.Nil(IgnoreExecResult(invoke().Exec("CREATE TABLE encryption_test (id int primary key, ssn bytea, ssn_index text, notes bytea, name text)")))
+
+	notes := "notes"
+	assert.Nil(invoke().Create(&encryptionTest{ID: 1, SSN: "123-45-6789", Notes: &notes, Name: "foo"}))
+	assert.Nil(invoke().Create(&encryptionTest{ID: 2, SSN: "987-65-4321", Name: "bar"}))
+
+	var stored []byte
+	_, err = invoke().Query("SELECT ssn FROM encryption_test WHERE id = 1").Scan(&stored)
+	assert.Nil(err)
+	assert.False(strings.Contains(string(stored), "123-45-6789"))
+
+	index, err := invoke().BlindIndex("987-65-4321")
+	assert.Nil(err)
+	var found encryptionTest
+	_, err = invoke().Query("SELECT * FROM encryption_test WHERE ssn_index = $1", index).Out(&found)
+	assert.Nil(err)
+	assert.Equal(2, found.ID)
+	assert.Equal("987-65-4321", found.SSN)
+	assert.Nil(found.Notes)
+
+	var all []encryptionTest
+	assert.Nil(invoke().All(&all))
+	assert.Len(all, 2)
+	for _, object := range all {
+		if object.ID == 1 {
+			assert.NotNil(object.Notes)
+			assert.Equal("notes", *object.Notes)
+			assert.Equal("123-45-6789", object.SSN)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package db
 
 import (
+	"github.com/lib/pq"
+
 	"github.com/blend/go-sdk/ex"
 )
 
@@ -37,6 +39,48 @@ const (
 	ErrRowsNotColumnsProvider ex.Class = "db: rows is not a columns provider"
 	// ErrTooManyRows is returned by Out if there is more than one row returned by the query
 	ErrTooManyRows ex.Class = "db: too many rows returned to map to single object"
+	// ErrColumnNotFound is returned by builders if a column is not mapped on the object.
+	ErrColumnNotFound ex.Class = "db: column not found"
+	// ErrUpdateNoValues is returned by the update builder if no columns are set.
+	ErrUpdateNoValues ex.Class = "db: update has no values to set"
+	// ErrStaleObject is returned by Update and Upsert if the object's version column
+	// doesn't match the row, i.e. it was changed since the object was read.
+	ErrStaleObject ex.Class = "db: object is stale; the row version has changed"
+	// ErrVersionNotInteger is returned if a version column is not an integer.
+	ErrVersionNotInteger ex.Class = "db: version column is not an integer"
+	// ErrNoSoftDeleteColumn is returned by Restore if the object has no soft delete column.
+	ErrNoSoftDeleteColumn ex.Class = "db: no soft delete column defined on object"
+	// ErrCursorRequiresTx is returned by a cursor if its invocation's db can neither be used as nor begin a transaction.
+	ErrCursorRequiresTx ex.Class = "db: cursor requires a transaction"
+	// ErrCursorNoRow is returned by reading a cursor that is not on a row.
+	ErrCursorNoRow ex.Class = "db: cursor is not on a row; did you call next?"
+	// ErrLockNotHeld is returned by unlocking an advisory lock that is not held.
+	ErrLockNotHeld ex.Class = "db: advisory lock is not held"
+	// ErrNamedParameterMissing is returned if a named parameter is missing from the argument it's bound from.
+	ErrNamedParameterMissing ex.Class = "db: named parameter missing from argument"
+	// ErrNamedParameterUnused is returned if a map argument has a key that isn't a named parameter of the statement.
+	ErrNamedParameterUnused ex.Class = "db: named parameter argument unused by statement"
+	// ErrNamedParameterEmpty is returned if a named parameter is bound to an empty slice.
+	ErrNamedParameterEmpty ex.Class = "db: named parameter slice is empty"
+	// ErrNamedParameterInvalid is returned if named parameters are bound from an argument that isn't a map or struct.
+	ErrNamedParameterInvalid ex.Class = "db: named parameter argument must be a map with string keys or a struct"
+	// ErrPaginationKeyUnset is returned by Paginate if the key page tokens are signed with is unset.
+	ErrPaginationKeyUnset ex.Class = "db: pagination key is unset"
+	// ErrPaginationSortNullable is returned by Paginate if a sort column is nullable, as rows with null sort values can't be paged past.
+	ErrPaginationSortNullable ex.Class = "db: pagination sort column is nullable"
+	// ErrPageTokenInvalid is returned by Paginate if a page token's signature doesn't match, or it was issued for a different sort.
+	ErrPageTokenInvalid ex.Class = "db: page token is invalid"
+	// ErrRelationNotFound is returned if a preloaded relation is not declared on the type.
+	ErrRelationNotFound ex.Class = "db: relation not found"
+	// ErrRelationInvalid is returned if a relation tag is invalid, or its types can't be related.
+	ErrRelationInvalid ex.Class = "db: relation is invalid"
+	// ErrEncrypterUnset is returned if an object with encrypted columns is written or read without an encrypter.
+	ErrEncrypterUnset ex.Class = "db: column encrypter is unset"
+	// ErrBlindIndexKeyUnset is returned if an object with blind index columns is written without a blind index key.
+	ErrBlindIndexKeyUnset ex.Class = "db: blind index key is unset"
+	// ErrEncryptedColumnInvalid is returned if an encrypted column's field isn't a string or byte slice, or a
+	// blind index column's source column isn't encrypted.
+	ErrEncryptedColumnInvalid ex.Class = "db: encrypted column is invalid"
 )
 
 // IsConfigUnset returns if the error is an `ErrConfigUnset`.
@@ -79,6 +123,68 @@ func IsPlanCacheKeyUnset(err error) bool {
 	return ex.Is(err, ErrPlanCacheKeyUnset)
 }
 
+// IsColumnNotFound returns if the error is an `ErrColumnNotFound`.
+func IsColumnNotFound(err error) bool {
+	return ex.Is(err, ErrColumnNotFound)
+}
+
+// IsStaleObject returns if the error is an `ErrStaleObject`.
+func IsStaleObject(err error) bool {
+	return ex.Is(err, ErrStaleObject)
+}
+
+// IsNoSoftDeleteColumn returns if the error is an `ErrNoSoftDeleteColumn`.
+func IsNoSoftDeleteColumn(err error) bool {
+	return ex.Is(err, ErrNoSoftDeleteColumn)
+}
+
+// IsNamedParameterMissing returns if the error is an `ErrNamedParameterMissing`.
+func IsNamedParameterMissing(err error) bool {
+	return ex.Is(err, ErrNamedParameterMissing)
+}
+
+// IsNamedParameterUnused returns if the error is an `ErrNamedParameterUnused`.
+func IsNamedParameterUnused(err error) bool {
+	return ex.Is(err, ErrNamedParameterUnused)
+}
+
+// IsPageTokenInvalid returns if the error is an `ErrPageTokenInvalid`.
+func IsPageTokenInvalid(err error) bool {
+	return ex.Is(err, ErrPageTokenInvalid)
+}
+
+// IsRelationNotFound returns if the error is an `ErrRelationNotFound`.
+func IsRelationNotFound(err error) bool {
+	return ex.Is(err, ErrRelationNotFound)
+}
+
+// IsSerializationFailure returns if the error is, or wraps, a postgres serialization failure.
+func IsSerializationFailure(err error) bool {
+	return PQErrorCode(err) == PQCodeSerializationFailure
+}
+
+// IsDeadlockDetected returns if the error is, or wraps, a postgres deadlock error.
+func IsDeadlockDetected(err error) bool {
+	return PQErrorCode(err) == PQCodeDeadlockDetected
+}
+
+// IsTxRetryable returns if a transaction that failed with the error can be retried.
+func IsTxRetryable(err error) bool {
+	return IsSerializationFailure(err) || IsDeadlockDetected(err)
+}
+
+// PQErrorCode returns the code of a postgres error, following exception classes and inner errors.
+// It returns an empty string if the error is not a postgres error.
+func PQErrorCode(err error) string {
+	for err != nil {
+		if typed, ok := ex.ErrClass(err).(*pq.Error); ok {
+			return string(typed.Code)
+		}
+		err = ex.ErrInner(err)
+	}
+	return ""
+}
+
 // Error returns a new exception by parsing (potentially)
 // a driver error into relevant pieces.
 func Error(err error, options ...ex.Option) error {
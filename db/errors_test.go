@@ -3,6 +3,8 @@ package db
 import (
 	"testing"
 
+	"github.com/lib/pq"
+
 	"github.com/blend/go-sdk/assert"
 	"github.com/blend/go-sdk/ex"
 )
@@ -18,3 +20,21 @@ func TestError(t *testing.T) {
 	err = ex.New("this is only a test")
 	assert.True(ex.Is(Error(err), ex.Class("this is only a test")))
 }
+
+func TestPQErrorCode(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(PQErrorCode(nil))
+	assert.Empty(PQErrorCode(ex.New("this is only a test")))
+
+	serialization := &pq.Error{Code: PQCodeSerializationFailure}
+	assert.Equal(PQCodeSerializationFailure, PQErrorCode(serialization))
+	assert.True(IsSerializationFailure(Error(serialization, ex.OptMessage("select 1"))))
+	assert.True(IsTxRetryable(serialization))
+
+	deadlock := ex.Nest(ex.New("outer"), Error(&pq.Error{Code: PQCodeDeadlockDetected}))
+	assert.True(IsDeadlockDetected(deadlock))
+	assert.True(IsTxRetryable(deadlock))
+
+	assert.False(IsTxRetryable(&pq.Error{Code: "23505"}))
+}
@@ -0,0 +1,200 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// BeforeCreateHook is a type that is called before it is inserted by `Create`, `CreateMany` and `Upsert`.
+// `Upsert` only calls it if the object's row doesn't exist yet.
+type BeforeCreateHook interface {
+	BeforeCreate(context.Context) error
+}
+
+// AfterCreateHook is a type that is called after it is inserted by `Create`, `CreateMany` and `Upsert`.
+// `Upsert` only calls it if the object's row didn't exist yet.
+type AfterCreateHook interface {
+	AfterCreate(context.Context) error
+}
+
+// BeforeUpdateHook is a type that is called before it is written by `Update` and `Upsert`.
+// `Upsert` only calls it if the object's row exists.
+type BeforeUpdateHook interface {
+	BeforeUpdate(context.Context) error
+}
+
+// AfterUpdateHook is a type that is called after it is written by `Update` and `Upsert`.
+// `Upsert` only calls it if the object's row existed.
+type AfterUpdateHook interface {
+	AfterUpdate(context.Context) error
+}
+
+// BeforeDeleteHook is a type that is called before it is deleted by `Delete`.
+type BeforeDeleteHook interface {
+	BeforeDelete(context.Context) error
+}
+
+// AfterGetHook is a type that is called after it is read by `Get`, `All`, and the query `Out` and `OutMany` methods.
+type AfterGetHook interface {
+	AfterGet(context.Context) error
+}
+
+// txBeginner is a db that can begin a transaction, i.e. a `*sql.DB`.
+type txBeginner interface {
+	BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)
+}
+
+// runHooked runs an operation and its hooks.
+//
+// The invocation's cancel is deferred until the hooks have run. If `inTx` is set and the
+// invocation isn't already in a transaction, the operation is run in one, so that an error
+// from an after hook rolls back the operation.
+func (i *Invocation) runHooked(inTx bool, action func() error) (err error) {
+	if cancel := i.Cancel; cancel != nil {
+		i.Cancel = nil
+		defer cancel()
+	}
+	beginner, ok := i.DB.(txBeginner)
+	if !inTx || !ok {
+		err = action()
+		return
+	}
+
+	var tx *sql.Tx
+	if tx, err = beginner.BeginTx(i.Context, nil); err != nil {
+		err = Error(err)
+		return
+	}
+	db, replica, selectReplica := i.DB, i.Replica, i.selectReplica
+	i.DB = tx
+	i.usePrimary()
+	defer func() {
+		i.DB, i.Replica, i.selectReplica = db, replica, selectReplica
+		if r := recover(); r != nil {
+			err = ex.Nest(ex.New(r), Error(tx.Rollback()))
+			return
+		}
+		if err != nil {
+			err = ex.Nest(err, Error(tx.Rollback()))
+			return
+		}
+		err = Error(tx.Commit())
+	}()
+	err = action()
+	return
+}
+
+// hookContext returns the context hooks are called with.
+// If the invocation is in a transaction, it is available to hooks with `TxFromContext`,
+// and `InTx` calls from hooks run in a savepoint of it.
+func (i *Invocation) hookContext() context.Context {
+	ctx := i.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if tx, ok := i.DB.(*sql.Tx); ok && TxFromContext(ctx) != tx {
+		return withTxState(ctx, &txState{Tx: tx})
+	}
+	return ctx
+}
+
+func (i *Invocation) beforeCreate(object interface{}) error {
+	if hook, ok := object.(BeforeCreateHook); ok {
+		return Error(hook.BeforeCreate(i.hookContext()))
+	}
+	return nil
+}
+
+func (i *Invocation) afterCreate(object interface{}) error {
+	if hook, ok := object.(AfterCreateHook); ok {
+		return Error(hook.AfterCreate(i.hookContext()))
+	}
+	return nil
+}
+
+func (i *Invocation) beforeUpdate(object interface{}) error {
+	if hook, ok := object.(BeforeUpdateHook); ok {
+		return Error(hook.BeforeUpdate(i.hookContext()))
+	}
+	return nil
+}
+
+func (i *Invocation) afterUpdate(object interface{}) error {
+	if hook, ok := object.(AfterUpdateHook); ok {
+		return Error(hook.AfterUpdate(i.hookContext()))
+	}
+	return nil
+}
+
+func (i *Invocation) beforeDelete(object interface{}) error {
+	if hook, ok := object.(BeforeDeleteHook); ok {
+		return Error(hook.BeforeDelete(i.hookContext()))
+	}
+	return nil
+}
+
+func (i *Invocation) afterGet(object interface{}) error {
+	if hook, ok := object.(AfterGetHook); ok {
+		return Error(hook.AfterGet(i.hookContext()))
+	}
+	return nil
+}
+
+// sliceLen returns the length of a slice, or a pointer to a slice.
+func sliceLen(collection interface{}) int {
+	if collectionValue := ReflectValue(collection); collectionValue.Kind() == reflect.Slice {
+		return collectionValue.Len()
+	}
+	return 0
+}
+
+// eachElement calls an action with a reference to each element of a slice, or a pointer to a slice,
+// starting at a given index.
+func eachElement(collection interface{}, from int, action func(interface{}) error) error {
+	collectionValue := ReflectValue(collection)
+	if collectionValue.Kind() != reflect.Slice {
+		return nil
+	}
+	for index := from; index < collectionValue.Len(); index++ {
+		element := collectionValue.Index(index)
+		if element.Kind() != reflect.Ptr && element.CanAddr() {
+			element = element.Addr()
+		}
+		if err := action(element.Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// anyElement returns if a predicate is true for a reference to any element of a slice, or a pointer to a slice.
+func anyElement(collection interface{}, predicate func(interface{}) bool) (found bool) {
+	_ = eachElement(collection, 0, func(element interface{}) error {
+		found = found || predicate(element)
+		return nil
+	})
+	return
+}
+
+func isAfterCreateHook(object interface{}) bool {
+	_, ok := object.(AfterCreateHook)
+	return ok
+}
+
+func isAfterUpdateHook(object interface{}) bool {
+	_, ok := object.(AfterUpdateHook)
+	return ok
+}
+
+// isUpsertHook returns if an object implements any of the hooks `Upsert` calls.
+func isUpsertHook(object interface{}) bool {
+	switch object.(type) {
+	case BeforeCreateHook, AfterCreateHook, BeforeUpdateHook, AfterUpdateHook:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,241 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/uuid"
+)
+
+const errHookTest ex.Class = "hook test"
+
+type hookTest struct {
+	ID         string    `db:"id,pk"`
+	Email      string    `db:"email"`
+	CreatedUTC time.Time `db:"created_utc"`
+	UpdatedUTC time.Time `db:"updated_utc"`
+
+	Fail  bool     `db:"-"`
+	Calls []string `db:"-"`
+}
+
+func (ht hookTest) TableName() string {
+	return "hook_test"
+}
+
+func (ht *hookTest) call(ctx context.Context, name string) error {
+	ht.Calls = append(ht.Calls, name)
+	if ht.Fail {
+		return ex.New(errHookTest)
+	}
+	return nil
+}
+
+func (ht *hookTest) BeforeCreate(ctx context.Context) error {
+	ht.CreatedUTC = time.Now().UTC()
+	ht.Email = strings.ToLower(ht.Email)
+	return ht.call(ctx, "before_create")
+}
+
+func (ht *hookTest) AfterCreate(ctx context.Context) error {
+	return ht.call(ctx, "after_create")
+}
+
+func (ht *hookTest) BeforeUpdate(ctx context.Context) error {
+	ht.UpdatedUTC = time.Now().UTC()
+	return ht.call(ctx, "before_update")
+}
+
+func (ht *hookTest) AfterUpdate(ctx context.Context) error {
+	return ht.call(ctx, "after_update")
+}
+
+func (ht *hookTest) BeforeDelete(ctx context.Context) error {
+	return ht.call(ctx, "before_delete")
+}
+
+func (ht *hookTest) AfterGet(ctx context.Context) error {
+	return ht.call(ctx, "after_get")
+}
+
+func TestInvocationHooksAbort(t *testing.T) {
+	assert := assert.New(t)
+
+	db := new(recordingDB)
+	i := &Invocation{DB: db, Context: context.Background(), BufferPool: defaultDB().BufferPool}
+
+	obj := &hookTest{ID: uuid.V4().String(), Email: "Foo@Example.com", Fail: true}
+	assert.True(ex.Is(i.Create(obj), errHookTest))
+	assert.Equal("foo@example.com", obj.Email)
+	assert.False(obj.CreatedUTC.IsZero())
+
+	_, err := i.Update(obj)
+	assert.True(ex.Is(err, errHookTest))
+	_, err = i.Delete(obj)
+	assert.True(ex.Is(err, errHookTest))
+	assert.True(ex.Is(i.CreateMany([]hookTest{{ID: uuid.V4().String(), Fail: true}}), errHookTest))
+
+	assert.Empty(db.Statements, "failed before hooks should abort the operation")
+	assert.Equal([]string{"before_create", "before_update", "before_delete"}, obj.Calls)
+}
+
+func TestInvocationHooksUpsertReadsStored(t *testing.T) {
+	assert := assert.New(t)
+
+	db := new(recordingDB)
+	i := &Invocation{DB: db, Context: context.Background(), BufferPool: defaultDB().BufferPool}
+
+	obj := &hookTest{ID: uuid.V4().String()}
+	assert.True(ex.Is(i.Upsert(obj), errRecordingDB))
+	assert.Len(db.Statements, 1)
+	assert.True(strings.HasSuffix(db.Statements[0], "FOR UPDATE"), "upsert should read the stored row to pick the hooks to call")
+	assert.Empty(obj.Calls)
+}
+
+func TestInvocationHooksSkipAfterOnError(t *testing.T) {
+	assert := assert.New(t)
+
+	db := new(recordingDB)
+	i := &Invocation{DB: db, Context: context.Background(), BufferPool: defaultDB().BufferPool}
+
+	obj := &hookTest{ID: uuid.V4().String()}
+	assert.True(ex.Is(i.Create(obj), errRecordingDB))
+	_, err := i.Update(obj)
+	assert.True(ex.Is(err, errRecordingDB))
+	assert.Len(db.Statements, 2)
+	assert.Equal([]string{"before_create", "before_update"}, obj.Calls)
+}
+
+func TestInvocationHooksCancel(t *testing.T) {
+	assert := assert.New(t)
+
+	var canceled bool
+	i := &Invocation{Cancel: func() { canceled = true }}
+	assert.Nil(i.runHooked(false, func() error {
+		assert.False(canceled, "cancel should be deferred until the hooks have run")
+		return nil
+	}))
+	assert.True(canceled)
+}
+
+func TestEachElement(t *testing.T) {
+	assert := assert.New(t)
+
+	objs := []hookTest{{ID: "one"}, {ID: "two"}, {ID: "three"}}
+	assert.Nil(eachElement(objs, 1, func(obj interface{}) error {
+		return obj.(*hookTest).AfterGet(context.Background())
+	}))
+	assert.Empty(objs[0].Calls)
+	assert.Equal([]string{"after_get"}, objs[1].Calls)
+	assert.Equal([]string{"after_get"}, objs[2].Calls)
+
+	ptrs := []*hookTest{{ID: "one"}}
+	assert.True(anyElement(&ptrs, isAfterCreateHook))
+	assert.False(anyElement([]benchObj{{}}, isAfterCreateHook))
+	assert.Equal(1, sliceLen(&ptrs))
+	assert.Zero(sliceLen(benchObj{}))
+}
+
+func createHookTestTable(tx *sql.Tx) error {
+	return IgnoreExecResult(defaultDB().Invoke(OptTx(tx)).Exec("CREATE TABLE IF NOT EXISTS hook_test (id varchar(255) primary key, email varchar(255), created_utc timestamp, updated_utc timestamp)"))
+}
+
+type hookTxTest struct {
+	hookTest `db:",inline"`
+}
+
+func (htt *hookTxTest) AfterCreate(ctx context.Context) error {
+	if TxFromContext(ctx) == nil {
+		return ex.New("hook should be called in the invocation's transaction")
+	}
+	return htt.hookTest.AfterCreate(ctx)
+}
+
+func TestConnectionHooks(t *testing.T) {
+	assert := assert.New(t)
+	tx, err := defaultDB().Begin()
+	assert.Nil(err)
+	defer tx.Rollback()
+
+	assert.Nil(createHookTestTable(tx))
+
+	obj := &hookTxTest{hookTest{ID: uuid.V4().String(), Email: "Foo@Example.com"}}
+	assert.Nil(defaultDB().Invoke(OptTx(tx)).Create(obj))
+	assert.Equal([]string{"before_create", "after_create"}, obj.Calls)
+
+	var verify hookTest
+	found, err := defaultDB().Invoke(OptTx(tx)).Get(&verify, obj.ID)
+	assert.Nil(err)
+	assert.True(found)
+	assert.Equal("foo@example.com", verify.Email)
+	assert.Equal([]string{"after_get"}, verify.Calls)
+
+	var all []hookTest
+	assert.Nil(defaultDB().Invoke(OptTx(tx)).All(&all))
+	assert.NotEmpty(all)
+	assert.Equal([]string{"after_get"}, all[0].Calls)
+
+	_, err = defaultDB().Invoke(OptTx(tx)).Update(&verify)
+	assert.Nil(err)
+	assert.False(verify.UpdatedUTC.IsZero())
+
+	_, err = defaultDB().Invoke(OptTx(tx)).Delete(&verify)
+	assert.Nil(err)
+}
+
+func TestConnectionHooksUpsert(t *testing.T) {
+	assert := assert.New(t)
+	tx, err := defaultDB().Begin()
+	assert.Nil(err)
+	defer tx.Rollback()
+
+	assert.Nil(createHookTestTable(tx))
+
+	obj := &hookTest{ID: uuid.V4().String(), Email: "foo@example.com"}
+	assert.Nil(defaultDB().Invoke(OptTx(tx)).Upsert(obj))
+	assert.Equal([]string{"before_create", "after_create"}, obj.Calls)
+
+	created := obj.CreatedUTC
+
+	obj.Calls = nil
+	obj.Email = "bar@example.com"
+	assert.Nil(defaultDB().Invoke(OptTx(tx)).Upsert(obj))
+	assert.Equal([]string{"before_update", "after_update"}, obj.Calls)
+	assert.Equal(created, obj.CreatedUTC, "an upsert that updates should not call the create hooks")
+
+	var verify hookTest
+	_, err = defaultDB().Invoke(OptTx(tx)).Get(&verify, obj.ID)
+	assert.Nil(err)
+	assert.Equal("bar@example.com", verify.Email)
+	assert.False(verify.UpdatedUTC.IsZero())
+}
+
+func TestConnectionHooksRollback(t *testing.T) {
+	assert := assert.New(t)
+
+	tx, err := defaultDB().Begin()
+	assert.Nil(err)
+	assert.Nil(createHookTestTable(tx))
+	assert.Nil(tx.Commit())
+
+	obj := &hookFailAfterCreate{hookTest{ID: uuid.V4().String()}}
+	assert.True(ex.Is(defaultDB().Invoke().Create(obj), errHookTest))
+
+	var verify hookTest
+	found, err := defaultDB().Invoke().Get(&verify, obj.ID)
+	assert.Nil(err)
+	assert.False(found, "a failed after hook should roll back the create")
+}
+
+type hookFailAfterCreate struct {
+	hookTest `db:",inline"`
+}
+
+func (hfac *hookFailAfterCreate) AfterCreate(ctx context.Context) error {
+	return ex.New(errHookTest)
+}
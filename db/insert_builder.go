@@ -0,0 +1,95 @@
+package db
+
+var (
+	_ Builder = (*InsertBuilder)(nil)
+)
+
+// Insert returns an insert builder for an object.
+// It inserts the object's columns that aren't readonly or auto by default.
+func Insert(object DatabaseMapped) *InsertBuilder {
+	return &InsertBuilder{
+		object:  object,
+		table:   TableName(object),
+		columns: CachedColumnCollectionFromInstance(object),
+	}
+}
+
+// InsertBuilder builds an insert statement.
+type InsertBuilder struct {
+	object    DatabaseMapped
+	table     string
+	columns   *ColumnCollection
+	fields    []string
+	conflict  []string
+	doNothing bool
+	returning []string
+}
+
+// Columns restricts the inserted columns.
+func (ib *InsertBuilder) Columns(columns ...string) *InsertBuilder {
+	ib.fields = append(ib.fields, columns...)
+	return ib
+}
+
+// OnConflictDoNothing skips the insert if it conflicts on the given columns,
+// or on any constraint if no columns are given.
+func (ib *InsertBuilder) OnConflictDoNothing(columns ...string) *InsertBuilder {
+	ib.conflict = columns
+	ib.doNothing = true
+	return ib
+}
+
+// Returning sets the columns returned by the statement.
+func (ib *InsertBuilder) Returning(columns ...string) *InsertBuilder {
+	ib.returning = append(ib.returning, columns...)
+	return ib
+}
+
+// Label implements Builder.
+func (ib *InsertBuilder) Label() string {
+	return ib.table + "_insert"
+}
+
+// Build implements Builder.
+func (ib *InsertBuilder) Build() (string, []interface{}, error) {
+	s := NewStatement(ib.table, ib.columns)
+
+	writeCols := ib.columns.WriteColumns()
+	if len(ib.fields) > 0 {
+		var selected []Column
+		lookup := ib.columns.Lookup()
+		for _, field := range ib.fields {
+			if col, ok := lookup[field]; ok {
+				selected = append(selected, *col)
+			}
+		}
+		writeCols = newColumnCollectionFromColumns(selected)
+	}
+
+	s.WriteString("INSERT INTO " + ib.table + " (")
+	if len(ib.fields) > 0 {
+		s.WriteColumns(ib.fields...)
+	} else {
+		s.WriteColumns(writeCols.ColumnNames()...)
+	}
+	s.WriteString(") VALUES (")
+	for index, value := range writeCols.ColumnValues(ib.object) {
+		if index > 0 {
+			s.WriteString(",")
+		}
+		s.WriteArg(value)
+	}
+	s.WriteString(")")
+
+	if ib.doNothing {
+		s.WriteString(" ON CONFLICT")
+		if len(ib.conflict) > 0 {
+			s.WriteString(" (")
+			s.WriteColumns(ib.conflict...)
+			s.WriteString(")")
+		}
+		s.WriteString(" DO NOTHING")
+	}
+	s.WriteReturning(ib.returning)
+	return s.Build()
+}
@@ -0,0 +1,31 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestInsertBuilder(t *testing.T) {
+	assert := assert.New(t)
+
+	obj := builderTestObj{Email: "foo@bar.com", Name: "foo", Meta: map[string]string{"foo": "bar"}}
+	ib := Insert(obj)
+	assert.Equal("builder_test_insert", ib.Label())
+	statement, args, err := ib.Returning("id").Build()
+	assert.Nil(err)
+	assert.Equal("INSERT INTO builder_test (email,name,meta) VALUES ($1,$2,$3) RETURNING id", statement)
+	assert.Equal([]interface{}{"foo@bar.com", "foo", `{"foo":"bar"}`}, args)
+
+	statement, args, err = Insert(&obj).Columns("email").OnConflictDoNothing("email").Build()
+	assert.Nil(err)
+	assert.Equal("INSERT INTO builder_test (email) VALUES ($1) ON CONFLICT (email) DO NOTHING", statement)
+	assert.Equal([]interface{}{"foo@bar.com"}, args)
+
+	statement, _, err = Insert(&obj).Columns("email").OnConflictDoNothing().Build()
+	assert.Nil(err)
+	assert.Equal("INSERT INTO builder_test (email) VALUES ($1) ON CONFLICT DO NOTHING", statement)
+
+	_, _, err = Insert(obj).Columns("emial").Build()
+	assert.True(IsColumnNotFound(err))
+}
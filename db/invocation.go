@@ -1,6 +1,7 @@
 package db
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
@@ -16,9 +17,24 @@ import (
 // Invocation is a specific operation against a context.
 type Invocation struct {
 	DB DB
+	// Replica is the read replica read only work is routed to, if set.
+	Replica DB
+	// ReadOnly routes `Exec` and `Query` to the replica.
+	ReadOnly bool
+	// Unscoped includes soft deleted rows in reads, and makes `Delete` remove rows.
+	Unscoped bool
+	// Preload are the relations loaded for the objects read by `Get`, `All`, `Out` and `OutMany`.
+	Preload []string
+	// AuditSink is where records of changes to audited columns are written, if set.
+	AuditSink AuditSink
+	// Encrypter encrypts and decrypts the values of encrypted columns.
+	Encrypter ColumnEncrypter
+	// BlindIndexKey is the key blind indexes of encrypted columns are computed with.
+	BlindIndexKey []byte
 
 	/* invocation state */
-	Label string
+	Label         string
+	selectReplica func() *Replica
 
 	/* context */
 	Context context.Context
@@ -28,6 +44,7 @@ type Invocation struct {
 	Config     Config
 	Log        logger.Triggerable
 	BufferPool *bufferutil.Pool
+	PlanCache  *PlanCache
 
 	/* logging hooks */
 	StatementInterceptor StatementInterceptor
@@ -41,7 +58,13 @@ func (i *Invocation) Exec(statement string, args ...interface{}) (res sql.Result
 	statement = i.Start(statement)
 	defer func() { err = i.Finish(statement, recover(), res, err) }()
 
-	res, err = i.DB.ExecContext(i.Context, statement, args...)
+	var db DB
+	if i.ReadOnly {
+		db = i.ReadDB()
+	} else {
+		db = i.WriteDB()
+	}
+	res, err = db.ExecContext(i.Context, statement, args...)
 	if err != nil {
 		err = Error(err)
 		return
@@ -50,6 +73,11 @@ func (i *Invocation) Exec(statement string, args ...interface{}) (res sql.Result
 }
 
 // Query returns a new query object for a given sql query and arguments.
+//
+// Selects run against the replica if one is set, unless they lock rows with a `FOR UPDATE`
+// or `FOR SHARE` clause; other statements, e.g. `INSERT ... RETURNING`, run against the primary
+// unless the invocation is read only. Selects that write, e.g. by calling `nextval`, should
+// be run with `OptPrimary`.
 func (i *Invocation) Query(statement string, args ...interface{}) *Query {
 	return &Query{
 		Invocation: i,
@@ -59,6 +87,8 @@ func (i *Invocation) Query(statement string, args ...interface{}) *Query {
 }
 
 // Get returns a given object based on a group of primary key ids within a transaction.
+// Soft deleted rows are excluded unless the invocation is unscoped.
+// It calls the object's `AfterGet` hook if it implements it and is found.
 func (i *Invocation) Get(object DatabaseMapped, ids ...interface{}) (found bool, err error) {
 	if len(ids) == 0 {
 		err = Error(ErrInvalidIDs)
@@ -70,18 +100,38 @@ func (i *Invocation) Get(object DatabaseMapped, ids ...interface{}) (found bool,
 		err = Error(err)
 		return
 	}
-	return i.Query(queryBody, ids...).Out(object)
+	return i.readQuery(queryBody, ids...).Out(object)
 }
 
 // All returns all rows of an object mapped table wrapped in a transaction.
+// Soft deleted rows are excluded unless the invocation is unscoped.
 func (i *Invocation) All(collection interface{}) (err error) {
 	var queryBody string
 	i.Label, queryBody = i.generateGetAll(collection)
-	return i.Query(queryBody).OutMany(collection)
+	return i.readQuery(queryBody).OutMany(collection)
 }
 
 // Create writes an object to the database within a transaction.
-func (i *Invocation) Create(object DatabaseMapped) (err error) {
+// It calls the object's `BeforeCreate` and `AfterCreate` hooks if it implements them.
+func (i *Invocation) Create(object DatabaseMapped) error {
+	audited := i.auditColumns(object)
+	return i.runHooked(isAfterCreateHook(object) || audited != nil, func() error {
+		if err := i.beforeCreate(object); err != nil {
+			return err
+		}
+		if err := i.create(object); err != nil {
+			return err
+		}
+		if audited != nil {
+			if err := i.writeAudit(AuditActionCreate, audited, nil, object); err != nil {
+				return err
+			}
+		}
+		return i.afterCreate(object)
+	})
+}
+
+func (i *Invocation) create(object DatabaseMapped) (err error) {
 	var queryBody string
 	var writeCols, autos *ColumnCollection
 	var res sql.Result
@@ -89,9 +139,13 @@ func (i *Invocation) Create(object DatabaseMapped) (err error) {
 
 	i.Label, queryBody, writeCols, autos = i.generateCreate(object)
 
+	values, err := i.writeValues(writeCols, object)
+	if err != nil {
+		return
+	}
 	queryBody = i.Start(queryBody)
 	if autos.Len() == 0 {
-		if res, err = i.DB.ExecContext(i.Context, queryBody, writeCols.ColumnValues(object)...); err != nil {
+		if res, err = i.WriteDB().ExecContext(i.Context, queryBody, values...); err != nil {
 			err = Error(err)
 			return
 		}
@@ -99,7 +153,7 @@ func (i *Invocation) Create(object DatabaseMapped) (err error) {
 	}
 
 	autoValues := i.AutoValues(autos)
-	if err = i.DB.QueryRowContext(i.Context, queryBody, writeCols.ColumnValues(object)...).Scan(autoValues...); err != nil {
+	if err = i.WriteDB().QueryRowContext(i.Context, queryBody, values...).Scan(autoValues...); err != nil {
 		err = Error(err)
 		return
 	}
@@ -122,15 +176,32 @@ func (i *Invocation) CreateIfNotExists(object DatabaseMapped) (err error) {
 
 	i.Label, queryBody, writeCols = i.generateCreateIfNotExists(object)
 
+	values, err := i.writeValues(writeCols, object)
+	if err != nil {
+		return
+	}
 	queryBody = i.Start(queryBody)
-	if res, err = i.DB.ExecContext(i.Context, queryBody, writeCols.ColumnValues(object)...); err != nil {
+	if res, err = i.WriteDB().ExecContext(i.Context, queryBody, values...); err != nil {
 		err = Error(err)
 	}
 	return
 }
 
 // CreateMany writes many objects to the database in a single insert.
-func (i *Invocation) CreateMany(objects interface{}) (err error) {
+// It calls each object's `BeforeCreate` and `AfterCreate` hooks if it implements them.
+func (i *Invocation) CreateMany(objects interface{}) error {
+	return i.runHooked(anyElement(objects, isAfterCreateHook), func() error {
+		if err := eachElement(objects, 0, i.beforeCreate); err != nil {
+			return err
+		}
+		if err := i.createMany(objects); err != nil {
+			return err
+		}
+		return eachElement(objects, 0, i.afterCreate)
+	})
+}
+
+func (i *Invocation) createMany(objects interface{}) (err error) {
 	var queryBody string
 	var writeCols *ColumnCollection
 	var sliceValue reflect.Value
@@ -143,13 +214,18 @@ func (i *Invocation) CreateMany(objects interface{}) (err error) {
 		return
 	}
 
-	queryBody = i.Start(queryBody)
 	var colValues []interface{}
 	for row := 0; row < sliceValue.Len(); row++ {
-		colValues = append(colValues, writeCols.ColumnValues(sliceValue.Index(row).Interface())...)
+		var values []interface{}
+		if values, err = i.writeValues(writeCols, sliceValue.Index(row).Interface()); err != nil {
+			return
+		}
+		colValues = append(colValues, values...)
 	}
 
-	res, err = i.DB.ExecContext(i.Context, queryBody, colValues...)
+	queryBody = i.Start(queryBody)
+
+	res, err = i.WriteDB().ExecContext(i.Context, queryBody, colValues...)
 	if err != nil {
 		err = Error(err)
 		return
@@ -161,19 +237,70 @@ func (i *Invocation) CreateMany(objects interface{}) (err error) {
 // an error. If ErrTooManyRows is returned, it's important to note that due to https://github.com/golang/go/issues/7898,
 // the Update HAS BEEN APPLIED. Its on the developer using UPDATE to ensure his tags are correct and/or execute it in a
 // transaction and roll back on this error
+//
+// If the object has a version column, tagged `db:"...,version"`, the update only applies if the row's version matches
+// the object's, and increments it. If no rows match, ErrStaleObject is returned.
+//
+// It calls the object's `BeforeUpdate` and `AfterUpdate` hooks if it implements them.
 func (i *Invocation) Update(object DatabaseMapped) (updated bool, err error) {
+	audited := i.auditColumns(object)
+	err = i.runHooked(isAfterUpdateHook(object) || audited != nil, func() (err error) {
+		if err = i.beforeUpdate(object); err != nil {
+			return
+		}
+		var stored DatabaseMapped
+		if audited != nil {
+			if stored, err = i.auditStored(object); err != nil {
+				return
+			}
+		}
+		if updated, err = i.update(object); err != nil {
+			return
+		}
+		if updated && stored != nil {
+			if err = i.writeAudit(AuditActionUpdate, audited, stored, object); err != nil {
+				return
+			}
+		}
+		return i.afterUpdate(object)
+	})
+	return
+}
+
+func (i *Invocation) update(object DatabaseMapped) (updated bool, err error) {
 	var queryBody string
 	var pks, writeCols *ColumnCollection
+	var version *Column
 	var res sql.Result
 	defer func() { err = i.Finish(queryBody, recover(), res, err) }()
 
-	i.Label, queryBody, pks, writeCols = i.generateUpdate(object)
+	i.Label, queryBody, pks, writeCols, version = i.generateUpdate(object)
+
+	args, err := i.writeValues(writeCols, object)
+	if err != nil {
+		return
+	}
+	var nextVersion interface{}
+	if version != nil {
+		var currentVersion interface{}
+		if currentVersion, nextVersion, err = version.NextVersion(object); err != nil {
+			return
+		}
+		for index, col := range writeCols.Columns() {
+			if col.IsVersion {
+				args[index] = nextVersion
+			}
+		}
+		args = append(append(args, pks.ColumnValues(object)...), currentVersion)
+	} else {
+		args = append(args, pks.ColumnValues(object)...)
+	}
 
 	queryBody = i.Start(queryBody)
-	res, err = i.DB.ExecContext(
+	res, err = i.WriteDB().ExecContext(
 		i.Context,
 		queryBody,
-		append(writeCols.ColumnValues(object), pks.ColumnValues(object)...)...,
+		args...,
 	)
 	if err != nil {
 		err = Error(err)
@@ -185,6 +312,14 @@ func (i *Invocation) Update(object DatabaseMapped) (updated bool, err error) {
 	rowCount, _ := res.RowsAffected()
 	if rowCount > 0 {
 		updated = true
+		if version != nil {
+			if err = version.SetValue(object, nextVersion); err != nil {
+				return
+			}
+		}
+	} else if version != nil {
+		err = Error(ErrStaleObject, ex.OptMessagef("table: %s", TableName(object)))
+		return
 	}
 	if rowCount > 1 {
 		err = Error(ErrTooManyRows)
@@ -193,27 +328,90 @@ func (i *Invocation) Update(object DatabaseMapped) (updated bool, err error) {
 }
 
 // Upsert inserts the object if it doesn't exist already (as defined by its primary keys) or updates it wrapped in a transaction.
-func (i *Invocation) Upsert(object DatabaseMapped) (err error) {
+//
+// If the object has a version column, tagged `db:"...,version"`, an existing row is only updated if its version matches
+// the object's, and the version is incremented. If the row exists with a different version, ErrStaleObject is returned.
+//
+// If the object implements create or update hooks, or has audited columns, the stored row is read and locked first,
+// and only the hooks for the case that applies are called: the update hooks if the row exists, and the create hooks
+// if it doesn't. In the latter case the object is inserted, so a row created concurrently causes a unique violation
+// rather than being overwritten with values meant for a new row.
+func (i *Invocation) Upsert(object DatabaseMapped) error {
+	audited := i.auditColumns(object)
+	if audited == nil && !isUpsertHook(object) {
+		return i.runHooked(false, func() error {
+			return i.upsert(object)
+		})
+	}
+	return i.runHooked(true, func() error {
+		stored, err := i.auditStored(object)
+		if err != nil {
+			return err
+		}
+		if stored == nil {
+			if err := i.beforeCreate(object); err != nil {
+				return err
+			}
+			if err := i.create(object); err != nil {
+				return err
+			}
+			if audited != nil {
+				if err := i.writeAudit(AuditActionCreate, audited, nil, object); err != nil {
+					return err
+				}
+			}
+			return i.afterCreate(object)
+		}
+		if err := i.beforeUpdate(object); err != nil {
+			return err
+		}
+		if err := i.upsert(object); err != nil {
+			return err
+		}
+		if audited != nil {
+			if err := i.writeAudit(AuditActionUpdate, audited, stored, object); err != nil {
+				return err
+			}
+		}
+		return i.afterUpdate(object)
+	})
+}
+
+func (i *Invocation) upsert(object DatabaseMapped) (err error) {
 	var queryBody string
 	var autos, writeCols *ColumnCollection
+	var version *Column
 	defer func() { err = i.Finish(queryBody, recover(), nil, err) }()
 
-	i.Label, queryBody, autos, writeCols = i.generateUpsert(object)
+	i.Label, queryBody, autos, writeCols, version = i.generateUpsert(object)
 
+	returning := autos
+	if version != nil {
+		returning = autos.ConcatWith(newColumnCollectionFromColumns([]Column{*version}))
+	}
+
+	values, err := i.writeValues(writeCols, object)
+	if err != nil {
+		return
+	}
 	queryBody = i.Start(queryBody)
-	if autos.Len() == 0 {
-		if _, err = i.Exec(queryBody, writeCols.ColumnValues(object)...); err != nil {
+	if returning.Len() == 0 {
+		if _, err = i.Exec(queryBody, values...); err != nil {
 			return
 		}
 		return
 	}
 
-	autoValues := i.AutoValues(autos)
-	if err = i.DB.QueryRowContext(i.Context, queryBody, writeCols.ColumnValues(object)...).Scan(autoValues...); err != nil {
+	returningValues := i.AutoValues(returning)
+	if err = i.WriteDB().QueryRowContext(i.Context, queryBody, values...).Scan(returningValues...); err != nil {
+		if version != nil && ex.Is(err, sql.ErrNoRows) {
+			err = Error(ErrStaleObject, ex.OptMessagef("table: %s", TableName(object)))
+			return
+		}
 		err = Error(err)
 		return
 	}
-	if err = i.SetAutos(object, autos, autoValues); err != nil {
+	if err = i.SetAutos(object, returning, returningValues); err != nil {
 		err = Error(err)
 		return
 	}
@@ -222,6 +420,7 @@ func (i *Invocation) Upsert(object DatabaseMapped) (err error) {
 }
 
 // Exists returns a bool if a given object exists (utilizing the primary key columns if they exist) wrapped in a transaction.
+// Soft deleted rows are excluded unless the invocation is unscoped.
 func (i *Invocation) Exists(object DatabaseMapped) (exists bool, err error) {
 	var queryBody string
 	var pks *ColumnCollection
@@ -233,7 +432,7 @@ func (i *Invocation) Exists(object DatabaseMapped) (exists bool, err error) {
 	}
 	queryBody = i.Start(queryBody)
 	var value int
-	if queryErr := i.DB.QueryRowContext(i.Context, queryBody, pks.ColumnValues(object)...).Scan(&value); queryErr != nil && !ex.Is(queryErr, sql.ErrNoRows) {
+	if queryErr := i.ReadDB().QueryRowContext(i.Context, queryBody, pks.ColumnValues(object)...).Scan(&value); queryErr != nil && !ex.Is(queryErr, sql.ErrNoRows) {
 		err = Error(queryErr)
 		return
 	}
@@ -245,7 +444,39 @@ func (i *Invocation) Exists(object DatabaseMapped) (exists bool, err error) {
 // and potentially an error. If ErrTooManyRows is returned, it's important to note that due to
 // https://github.com/golang/go/issues/7898, the Delete HAS BEEN APPLIED on the current transaction. Its on the
 // developer using Delete to ensure their tags are correct and/or ensure theit Tx rolls back on this error.
+//
+// If the object has a soft delete column, tagged `db:"...,softdelete"`, the row is soft deleted by setting the column
+// to the current time, unless the invocation is unscoped. Rows that are already soft deleted are not deleted again.
+//
+// It calls the object's `BeforeDelete` hook if it implements it.
 func (i *Invocation) Delete(object DatabaseMapped) (deleted bool, err error) {
+	audited := i.auditColumns(object)
+	err = i.runHooked(audited != nil, func() (err error) {
+		if err = i.beforeDelete(object); err != nil {
+			return
+		}
+		var stored DatabaseMapped
+		if audited != nil {
+			if stored, err = i.auditStored(object); err != nil {
+				return
+			}
+		}
+		if deleted, err = i.deleteObject(object); err != nil {
+			return
+		}
+		if deleted && stored != nil {
+			err = i.writeAudit(AuditActionDelete, audited, stored, nil)
+		}
+		return
+	})
+	return
+}
+
+func (i *Invocation) deleteObject(object DatabaseMapped) (deleted bool, err error) {
+	if softDelete := CachedColumnCollectionFromInstance(object).SoftDeleteColumn(); softDelete != nil && !i.Unscoped {
+		return i.softDelete(object, softDelete)
+	}
+
 	var queryBody string
 	var pks *ColumnCollection
 	var res sql.Result
@@ -256,7 +487,7 @@ func (i *Invocation) Delete(object DatabaseMapped) (deleted bool, err error) {
 	}
 
 	queryBody = i.Start(queryBody)
-	res, err = i.DB.ExecContext(i.Context, queryBody, pks.ColumnValues(object)...)
+	res, err = i.WriteDB().ExecContext(i.Context, queryBody, pks.ColumnValues(object)...)
 	if err != nil {
 		err = Error(err)
 		return
@@ -273,6 +504,78 @@ func (i *Invocation) Delete(object DatabaseMapped) (deleted bool, err error) {
 	return
 }
 
+// HardDelete deletes an object's row from the database, even if the object has a soft delete column.
+// The invocation is only unscoped for the delete.
+func (i *Invocation) HardDelete(object DatabaseMapped) (deleted bool, err error) {
+	unscoped := i.Unscoped
+	i.Unscoped = true
+	defer func() { i.Unscoped = unscoped }()
+	return i.Delete(object)
+}
+
+// Restore clears the soft delete column of an object's row, and of the object.
+// Returns whether or not a soft deleted row was restored.
+func (i *Invocation) Restore(object DatabaseMapped) (restored bool, err error) {
+	var queryBody string
+	var pks *ColumnCollection
+	var softDelete *Column
+	var res sql.Result
+	defer func() { err = i.Finish(queryBody, recover(), res, err) }()
+
+	if i.Label, queryBody, pks, softDelete, err = i.generateRestore(object); err != nil {
+		return
+	}
+
+	queryBody = i.Start(queryBody)
+	res, err = i.WriteDB().ExecContext(i.Context, queryBody, pks.ColumnValues(object)...)
+	if err != nil {
+		err = Error(err)
+		return
+	}
+	ra64, _ := res.RowsAffected()
+	if ra64 > 0 {
+		restored = true
+		if err = softDelete.SetValue(object, nil); err != nil {
+			return
+		}
+	}
+	if ra64 > 1 {
+		err = Error(ErrTooManyRows)
+	}
+	return
+}
+
+// softDelete sets the soft delete column of an object's row, and of the object, to the current time.
+func (i *Invocation) softDelete(object DatabaseMapped, softDelete *Column) (deleted bool, err error) {
+	var queryBody string
+	var pks *ColumnCollection
+	var res sql.Result
+	defer func() { err = i.Finish(queryBody, recover(), res, err) }()
+
+	if i.Label, queryBody, pks, err = i.generateSoftDelete(object, softDelete); err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	queryBody = i.Start(queryBody)
+	res, err = i.WriteDB().ExecContext(i.Context, queryBody, append([]interface{}{now}, pks.ColumnValues(object)...)...)
+	if err != nil {
+		err = Error(err)
+		return
+	}
+	ra64, _ := res.RowsAffected()
+	if ra64 > 0 {
+		deleted = true
+		if err = softDelete.SetValue(object, &now); err != nil {
+			return
+		}
+	}
+	if ra64 > 1 {
+		err = Error(ErrTooManyRows)
+	}
+	return
+}
+
 // --------------------------------------------------------------------------------
 // query body generators
 // --------------------------------------------------------------------------------
@@ -281,6 +584,7 @@ func (i *Invocation) generateGet(object DatabaseMapped) (cachePlan, queryBody st
 	tableName := TableName(object)
 
 	cols := CachedColumnCollectionFromInstance(object).NotReadOnly()
+	softDelete := cols.SoftDeleteColumn()
 	pks := cols.PrimaryKeys()
 	if pks.Len() == 0 {
 		err = Error(ErrNoPrimaryKey)
@@ -311,8 +615,9 @@ func (i *Invocation) generateGet(object DatabaseMapped) (cachePlan, queryBody st
 			queryBodyBuffer.WriteString(" AND ")
 		}
 	}
+	i.writeSoftDeleteScope(queryBodyBuffer, " AND ", softDelete)
 
-	cachePlan = fmt.Sprintf("%s_get", tableName)
+	cachePlan = i.scopedLabel(fmt.Sprintf("%s_get", tableName), softDelete)
 	queryBody = queryBodyBuffer.String()
 	return
 }
@@ -322,6 +627,7 @@ func (i *Invocation) generateGetAll(collection interface{}) (statementLabel, que
 	tableName := TableNameByType(collectionType)
 
 	cols := CachedColumnCollectionFromType(tableName, ReflectSliceType(collection)).NotReadOnly()
+	softDelete := cols.SoftDeleteColumn()
 
 	queryBodyBuffer := i.BufferPool.Get()
 	defer i.BufferPool.Put(queryBodyBuffer)
@@ -335,9 +641,10 @@ func (i *Invocation) generateGetAll(collection interface{}) (statementLabel, que
 	}
 	queryBodyBuffer.WriteString(" FROM ")
 	queryBodyBuffer.WriteString(tableName)
+	i.writeSoftDeleteScope(queryBodyBuffer, " WHERE ", softDelete)
 
 	queryBody = queryBodyBuffer.String()
-	statementLabel = tableName + "_get_all"
+	statementLabel = i.scopedLabel(tableName+"_get_all", softDelete)
 	return
 }
 
@@ -468,13 +775,14 @@ func (i *Invocation) generateCreateMany(objects interface{}) (queryBody string,
 	return
 }
 
-func (i *Invocation) generateUpdate(object DatabaseMapped) (statementLabel, queryBody string, pks, writeCols *ColumnCollection) {
+func (i *Invocation) generateUpdate(object DatabaseMapped) (statementLabel, queryBody string, pks, writeCols *ColumnCollection, version *Column) {
 	tableName := TableName(object)
 
 	cols := CachedColumnCollectionFromInstance(object)
 
 	pks = cols.PrimaryKeys()
 	writeCols = cols.WriteColumns()
+	version = writeCols.VersionColumn()
 
 	queryBodyBuffer := i.BufferPool.Get()
 	defer i.BufferPool.Put(queryBodyBuffer)
@@ -504,13 +812,18 @@ func (i *Invocation) generateUpdate(object DatabaseMapped) (statementLabel, quer
 			queryBodyBuffer.WriteString(" AND ")
 		}
 	}
+	if version != nil {
+		queryBodyBuffer.WriteString(" AND ")
+		queryBodyBuffer.WriteString(version.ColumnName)
+		queryBodyBuffer.WriteString(" = $" + strconv.Itoa(writeColIndex+pks.Len()+1))
+	}
 
 	queryBody = queryBodyBuffer.String()
 	statementLabel = tableName + "_update"
 	return
 }
 
-func (i *Invocation) generateUpsert(object DatabaseMapped) (statementLabel, queryBody string, autos, writeCols *ColumnCollection) {
+func (i *Invocation) generateUpsert(object DatabaseMapped) (statementLabel, queryBody string, autos, writeCols *ColumnCollection, version *Column) {
 	tableName := TableName(object)
 	cols := CachedColumnCollectionFromInstance(object)
 	updates := cols.NotReadOnly().NotAutos().NotPrimaryKeys().NotUniqueKeys()
@@ -522,6 +835,9 @@ func (i *Invocation) generateUpsert(object DatabaseMapped) (statementLabel, quer
 	autos = cols.Autos()
 	pks := cols.PrimaryKeys()
 	pkNames := pks.ColumnNames()
+	if pks.Len() > 0 {
+		version = writeCols.VersionColumn()
+	}
 
 	queryBodyBuffer := i.BufferPool.Get()
 	defer i.BufferPool.Put(queryBodyBuffer)
@@ -563,15 +879,28 @@ func (i *Invocation) generateUpsert(object DatabaseMapped) (statementLabel, quer
 		queryBodyBuffer.WriteString(") DO UPDATE SET ")
 
 		for i, col := range updateCols {
-			queryBodyBuffer.WriteString(col.ColumnName + " = " + tokenMap[col.ColumnName])
+			if col.IsVersion {
+				queryBodyBuffer.WriteString(col.ColumnName + " = " + tableName + "." + col.ColumnName + " + 1")
+			} else {
+				queryBodyBuffer.WriteString(col.ColumnName + " = " + tokenMap[col.ColumnName])
+			}
 			if i < (len(updateCols) - 1) {
 				queryBodyBuffer.WriteRune(',')
 			}
 		}
+		if version != nil {
+			queryBodyBuffer.WriteString(" WHERE " + tableName + "." + version.ColumnName + " = " + tokenMap[version.ColumnName])
+		}
 	}
-	if autos.Len() > 0 {
+	if autos.Len() > 0 || version != nil {
 		queryBodyBuffer.WriteString(" RETURNING ")
 		queryBodyBuffer.WriteString(autos.ColumnNamesCSV())
+		if version != nil {
+			if autos.Len() > 0 {
+				queryBodyBuffer.WriteRune(',')
+			}
+			queryBodyBuffer.WriteString(version.ColumnName)
+		}
 	}
 
 	queryBody = queryBodyBuffer.String()
@@ -581,7 +910,9 @@ func (i *Invocation) generateUpsert(object DatabaseMapped) (statementLabel, quer
 
 func (i *Invocation) generateExists(object DatabaseMapped) (statementLabel, queryBody string, pks *ColumnCollection, err error) {
 	tableName := TableName(object)
-	pks = CachedColumnCollectionFromInstance(object).PrimaryKeys()
+	cols := CachedColumnCollectionFromInstance(object)
+	softDelete := cols.SoftDeleteColumn()
+	pks = cols.PrimaryKeys()
 	if pks.Len() == 0 {
 		err = Error(ErrNoPrimaryKey)
 		return
@@ -601,7 +932,8 @@ func (i *Invocation) generateExists(object DatabaseMapped) (statementLabel, quer
 			queryBodyBuffer.WriteString(" AND ")
 		}
 	}
-	statementLabel = tableName + "_exists"
+	i.writeSoftDeleteScope(queryBodyBuffer, " AND ", softDelete)
+	statementLabel = i.scopedLabel(tableName+"_exists", softDelete)
 	queryBody = queryBodyBuffer.String()
 	return
 }
@@ -633,10 +965,129 @@ func (i *Invocation) generateDelete(object DatabaseMapped) (statementLabel, quer
 	return
 }
 
+func (i *Invocation) generateSoftDelete(object DatabaseMapped, softDelete *Column) (statementLabel, queryBody string, pks *ColumnCollection, err error) {
+	tableName := TableName(object)
+	pks = CachedColumnCollectionFromInstance(object).PrimaryKeys()
+	if pks.Len() == 0 {
+		err = Error(ErrNoPrimaryKey)
+		return
+	}
+	queryBodyBuffer := i.BufferPool.Get()
+	defer i.BufferPool.Put(queryBodyBuffer)
+
+	queryBodyBuffer.WriteString("UPDATE ")
+	queryBodyBuffer.WriteString(tableName)
+	queryBodyBuffer.WriteString(" SET ")
+	queryBodyBuffer.WriteString(softDelete.ColumnName)
+	queryBodyBuffer.WriteString(" = $1 WHERE ")
+	for i, pk := range pks.Columns() {
+		queryBodyBuffer.WriteString(pk.ColumnName)
+		queryBodyBuffer.WriteString(" = ")
+		queryBodyBuffer.WriteString("$" + strconv.Itoa(i+2))
+		queryBodyBuffer.WriteString(" AND ")
+	}
+	queryBodyBuffer.WriteString(softDelete.ColumnName)
+	queryBodyBuffer.WriteString(" IS NULL")
+	statementLabel = tableName + "_soft_delete"
+	queryBody = queryBodyBuffer.String()
+	return
+}
+
+func (i *Invocation) generateRestore(object DatabaseMapped) (statementLabel, queryBody string, pks *ColumnCollection, softDelete *Column, err error) {
+	tableName := TableName(object)
+	cols := CachedColumnCollectionFromInstance(object)
+	if softDelete = cols.SoftDeleteColumn(); softDelete == nil {
+		err = Error(ErrNoSoftDeleteColumn, ex.OptMessagef("table: %s", tableName))
+		return
+	}
+	pks = cols.PrimaryKeys()
+	if pks.Len() == 0 {
+		err = Error(ErrNoPrimaryKey)
+		return
+	}
+	queryBodyBuffer := i.BufferPool.Get()
+	defer i.BufferPool.Put(queryBodyBuffer)
+
+	queryBodyBuffer.WriteString("UPDATE ")
+	queryBodyBuffer.WriteString(tableName)
+	queryBodyBuffer.WriteString(" SET ")
+	queryBodyBuffer.WriteString(softDelete.ColumnName)
+	queryBodyBuffer.WriteString(" = NULL WHERE ")
+	for i, pk := range pks.Columns() {
+		queryBodyBuffer.WriteString(pk.ColumnName)
+		queryBodyBuffer.WriteString(" = ")
+		queryBodyBuffer.WriteString("$" + strconv.Itoa(i+1))
+		queryBodyBuffer.WriteString(" AND ")
+	}
+	queryBodyBuffer.WriteString(softDelete.ColumnName)
+	queryBodyBuffer.WriteString(" IS NOT NULL")
+	statementLabel = tableName + "_restore"
+	queryBody = queryBodyBuffer.String()
+	return
+}
+
+// writeSoftDeleteScope writes a predicate excluding soft deleted rows, unless the invocation is unscoped.
+func (i *Invocation) writeSoftDeleteScope(queryBodyBuffer *bytes.Buffer, prefix string, softDelete *Column) {
+	if softDelete == nil || i.Unscoped {
+		return
+	}
+	queryBodyBuffer.WriteString(prefix)
+	queryBodyBuffer.WriteString(softDelete.ColumnName)
+	queryBodyBuffer.WriteString(" IS NULL")
+}
+
+// scopedLabel returns a statement label that distinguishes unscoped statements
+// for objects with a soft delete column, as they differ from the scoped statements.
+func (i *Invocation) scopedLabel(label string, softDelete *Column) string {
+	if softDelete != nil && i.Unscoped {
+		return label + "_unscoped"
+	}
+	return label
+}
+
 // --------------------------------------------------------------------------------
 // helpers
 // --------------------------------------------------------------------------------
 
+// readQuery returns a query for read only work, that runs against the replica if one is set.
+func (i *Invocation) readQuery(statement string, args ...interface{}) *Query {
+	q := i.Query(statement, args...)
+	q.read = true
+	return q
+}
+
+// ReadDB returns the db read only work should run against.
+// It is the replica if one is set, unless a write has been made with a
+// read-your-writes context, otherwise it is the primary db.
+// The replica is selected the first time it is needed.
+func (i *Invocation) ReadDB() DB {
+	if HasWritten(i.Context) {
+		return i.DB
+	}
+	if i.Replica == nil && i.selectReplica != nil {
+		if replica := i.selectReplica(); replica != nil {
+			i.Replica = replica.Connection
+		}
+		i.selectReplica = nil
+	}
+	if i.Replica != nil {
+		return i.Replica
+	}
+	return i.DB
+}
+
+// usePrimary runs all work for the invocation against the primary db.
+func (i *Invocation) usePrimary() {
+	i.Replica = nil
+	i.selectReplica = nil
+}
+
+// WriteDB returns the primary db, recording the write on read-your-writes contexts.
+func (i *Invocation) WriteDB() DB {
+	markWritten(i.Context)
+	return i.DB
+}
+
 // AutoValues returns references to the auto updatd fields for a given column collection.
 func (i *Invocation) AutoValues(autos *ColumnCollection) []interface{} {
 	autoValues := make([]interface{}, autos.Len())
@@ -685,6 +1136,10 @@ func (i *Invocation) Finish(statement string, r interface{}, res sql.Result, err
 		qe.Label = i.Label
 		qe.Engine = i.Config.EngineOrDefault()
 		qe.Err = err
+		if res != nil {
+			// the error is ignored; not all statements report rows affected.
+			qe.RowsAffected, _ = res.RowsAffected()
+		}
 		i.Log.Trigger(i.Context, qe)
 	}
 	if i.TraceFinisher != nil && !IsSkipQueryLogging(i.Context) {
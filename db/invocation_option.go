@@ -50,17 +50,70 @@ func OptTimeout(d time.Duration) InvocationOption {
 }
 
 // OptTx is an invocation option that sets the invocation transaction.
+// Work in a transaction always runs against the primary.
 func OptTx(tx *sql.Tx) InvocationOption {
 	return func(i *Invocation) {
 		if tx != nil {
 			i.DB = tx
+			i.usePrimary()
 		}
 	}
 }
 
 // OptDB is an invocation option that sets the underlying invocation db.
+// It also clears the replica so that all work runs against the db.
 func OptDB(db DB) InvocationOption {
 	return func(i *Invocation) {
 		i.DB = db
+		i.usePrimary()
+	}
+}
+
+// OptPrimary is an invocation option that runs all work against the primary db.
+func OptPrimary() InvocationOption {
+	return func(i *Invocation) {
+		i.usePrimary()
+	}
+}
+
+// OptReadOnly is an invocation option that marks the invocation as read only,
+// routing `Exec` and `Query` to the replica as well as `Get`, `All` and `Exists`.
+func OptReadOnly() InvocationOption {
+	return func(i *Invocation) {
+		i.ReadOnly = true
+	}
+}
+
+// OptUnscoped is an invocation option that includes soft deleted rows in `Get`, `All` and `Exists`,
+// and makes `Delete` remove rows instead of soft deleting them.
+func OptUnscoped() InvocationOption {
+	return func(i *Invocation) {
+		i.Unscoped = true
+	}
+}
+
+// OptPreload is an invocation option that loads relations of the objects read by `Get`, `All`, `Out` and `OutMany`,
+// with one query per relation. Relations of relations are loaded with dotted paths, e.g. `LineItems.Product`.
+//
+//	var orders []Order
+//	err := conn.Invoke(db.OptPreload("Customer", "LineItems.Product")).All(&orders)
+//
+// See `Relation` for how relations are declared.
+func OptPreload(relations ...string) InvocationOption {
+	return func(i *Invocation) {
+		i.Preload = append(i.Preload, relations...)
+	}
+}
+
+// OptAudit is an invocation option that writes records of changes to audited columns, tagged `db:"...,audit"`,
+// made by `Create`, `Update`, `Upsert` and `Delete` to a sink, in the same transaction as the change.
+//
+//	ctx = db.WithAuditActor(ctx, session.UserID)
+//	_, err := conn.Invoke(db.OptContext(ctx), db.OptAudit(db.NewAuditTable(db.DefaultAuditTable))).Update(&user)
+//
+// See `AuditRecord` for what is recorded.
+func OptAudit(sink AuditSink) InvocationOption {
+	return func(i *Invocation) {
+		i.AuditSink = sink
 	}
 }
@@ -35,6 +35,10 @@ func TestInvocationOptions(t *testing.T) {
 	assert.NotNil(i.Cancel)
 	assert.NotNil(i.Context)
 
+	assert.False(i.Unscoped)
+	OptUnscoped()(i)
+	assert.True(i.Unscoped)
+
 	i.DB = defaultDB().Connection
 	assert.NotNil(i.DB)
 	OptTx(nil)(i)
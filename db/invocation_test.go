@@ -5,11 +5,13 @@ import (
 	"database/sql"
 	"fmt"
 	"io/ioutil"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/bufferutil"
 	"github.com/blend/go-sdk/logger"
 	"github.com/blend/go-sdk/uuid"
 )
@@ -547,3 +549,219 @@ func TestInvocationMetrics(t *testing.T) {
 	<-done
 	assert.NotZero(elapsed)
 }
+
+func (vt versionedTest) TableName() string {
+	return "versioned_test"
+}
+
+func TestInvocationGenerateVersioned(t *testing.T) {
+	assert := assert.New(t)
+
+	i := &Invocation{BufferPool: bufferutil.NewPool(1)}
+
+	_, queryBody, _, _, version := i.generateUpdate(versionedTest{})
+	assert.NotNil(version)
+	assert.Equal("UPDATE versioned_test SET id = $1,name = $2,version = $3 WHERE id = $4 AND version = $5", queryBody)
+
+	_, queryBody, _, _, version = i.generateUpsert(versionedTest{})
+	assert.NotNil(version)
+	assert.Equal("INSERT INTO versioned_test (id,name,version) VALUES ($1,$2,$3) ON CONFLICT (id) DO UPDATE SET name = $2,version = versioned_test.version + 1 WHERE versioned_test.version = $3 RETURNING version", queryBody)
+}
+
+func createVersionedTestTable(tx *sql.Tx) error {
+	return IgnoreExecResult(defaultDB().Invoke(OptTx(tx)).Exec("CREATE TABLE IF NOT EXISTS versioned_test (id varchar(255) primary key, name varchar(255), version bigint not null)"))
+}
+
+func TestConnectionUpdateVersioned(t *testing.T) {
+	assert := assert.New(t)
+	tx, err := defaultDB().Begin()
+	assert.Nil(err)
+	defer tx.Rollback()
+
+	assert.Nil(createVersionedTestTable(tx))
+
+	obj := &versionedTest{ID: uuid.V4().String(), Name: "one"}
+	assert.Nil(defaultDB().Invoke(OptTx(tx)).Create(obj))
+
+	var stale versionedTest
+	_, err = defaultDB().Invoke(OptTx(tx)).Get(&stale, obj.ID)
+	assert.Nil(err)
+
+	obj.Name = "two"
+	updated, err := defaultDB().Invoke(OptTx(tx)).Update(obj)
+	assert.Nil(err)
+	assert.True(updated)
+	assert.Equal(1, obj.Version)
+
+	stale.Name = "three"
+	updated, err = defaultDB().Invoke(OptTx(tx)).Update(&stale)
+	assert.True(IsStaleObject(err))
+	assert.False(updated)
+	assert.Zero(stale.Version)
+
+	var verify versionedTest
+	_, err = defaultDB().Invoke(OptTx(tx)).Get(&verify, obj.ID)
+	assert.Nil(err)
+	assert.Equal("two", verify.Name)
+	assert.Equal(1, verify.Version)
+}
+
+func TestConnectionUpsertVersioned(t *testing.T) {
+	assert := assert.New(t)
+	tx, err := defaultDB().Begin()
+	assert.Nil(err)
+	defer tx.Rollback()
+
+	assert.Nil(createVersionedTestTable(tx))
+
+	obj := &versionedTest{ID: uuid.V4().String(), Name: "one"}
+	assert.Nil(defaultDB().Invoke(OptTx(tx)).Upsert(obj))
+	assert.Zero(obj.Version)
+
+	stale := *obj
+
+	obj.Name = "two"
+	assert.Nil(defaultDB().Invoke(OptTx(tx)).Upsert(obj))
+	assert.Equal(1, obj.Version)
+
+	stale.Name = "three"
+	assert.True(IsStaleObject(defaultDB().Invoke(OptTx(tx)).Upsert(&stale)))
+
+	objs := []versionedTest{{ID: uuid.V4().String(), Name: "four"}, {ID: uuid.V4().String(), Name: "five", Version: 2}}
+	assert.Nil(defaultDB().Invoke(OptTx(tx)).CreateMany(objs))
+	var verify versionedTest
+	_, err = defaultDB().Invoke(OptTx(tx)).Get(&verify, objs[1].ID)
+	assert.Nil(err)
+	assert.Equal(2, verify.Version)
+}
+
+type softDeleteTest struct {
+	ID         string     `db:"id,pk"`
+	Name       string     `db:"name"`
+	DeletedUTC *time.Time `db:"deleted_utc,softdelete"`
+}
+
+func (sdt softDeleteTest) TableName() string {
+	return "soft_delete_test"
+}
+
+func TestInvocationGenerateSoftDelete(t *testing.T) {
+	assert := assert.New(t)
+
+	i := &Invocation{BufferPool: bufferutil.NewPool(1)}
+
+	label, queryBody, err := i.generateGet(softDeleteTest{})
+	assert.Nil(err)
+	assert.Equal("soft_delete_test_get", label)
+	assert.Equal("SELECT id,name,deleted_utc FROM soft_delete_test WHERE id = $1 AND deleted_utc IS NULL", queryBody)
+
+	label, queryBody = i.generateGetAll(&[]softDeleteTest{})
+	assert.Equal("soft_delete_test_get_all", label)
+	assert.Equal("SELECT id,name,deleted_utc FROM soft_delete_test WHERE deleted_utc IS NULL", queryBody)
+
+	label, queryBody, _, err = i.generateExists(softDeleteTest{})
+	assert.Nil(err)
+	assert.Equal("soft_delete_test_exists", label)
+	assert.Equal("SELECT 1 FROM soft_delete_test WHERE id = $1 AND deleted_utc IS NULL", queryBody)
+
+	label, queryBody, _, err = i.generateSoftDelete(softDeleteTest{}, CachedColumnCollectionFromInstance(softDeleteTest{}).SoftDeleteColumn())
+	assert.Nil(err)
+	assert.Equal("soft_delete_test_soft_delete", label)
+	assert.Equal("UPDATE soft_delete_test SET deleted_utc = $1 WHERE id = $2 AND deleted_utc IS NULL", queryBody)
+
+	label, queryBody, _, softDelete, err := i.generateRestore(softDeleteTest{})
+	assert.Nil(err)
+	assert.NotNil(softDelete)
+	assert.Equal("soft_delete_test_restore", label)
+	assert.Equal("UPDATE soft_delete_test SET deleted_utc = NULL WHERE id = $1 AND deleted_utc IS NOT NULL", queryBody)
+
+	_, _, _, _, err = i.generateRestore(benchObj{})
+	assert.True(IsNoSoftDeleteColumn(err))
+
+	i.Unscoped = true
+
+	label, queryBody, err = i.generateGet(softDeleteTest{})
+	assert.Nil(err)
+	assert.Equal("soft_delete_test_get_unscoped", label)
+	assert.Equal("SELECT id,name,deleted_utc FROM soft_delete_test WHERE id = $1", queryBody)
+
+	label, queryBody = i.generateGetAll(&[]softDeleteTest{})
+	assert.Equal("soft_delete_test_get_all_unscoped", label)
+	assert.Equal("SELECT id,name,deleted_utc FROM soft_delete_test", queryBody)
+
+	label, _, _, err = i.generateExists(softDeleteTest{})
+	assert.Nil(err)
+	assert.Equal("soft_delete_test_exists_unscoped", label)
+
+	label, _, err = i.generateGet(benchObj{})
+	assert.Nil(err)
+	assert.Equal("bench_object_get", label, "objects without a soft delete column should keep their labels")
+}
+
+func TestInvocationHardDeleteScope(t *testing.T) {
+	assert := assert.New(t)
+
+	recording := new(recordingDB)
+	i := (&Connection{BufferPool: bufferutil.NewPool(16)}).Invoke(OptDB(recording))
+	_, err := i.HardDelete(&softDeleteTest{ID: uuid.V4().String()})
+	assert.NotNil(err)
+	assert.Len(recording.Statements, 1)
+	assert.True(strings.HasPrefix(recording.Statements[0], "DELETE"), recording.Statements[0])
+	assert.False(i.Unscoped, "reads on the invocation after a hard delete should still exclude soft deleted rows")
+}
+
+func createSoftDeleteTestTable(tx *sql.Tx) error {
+	return IgnoreExecResult(defaultDB().Invoke(OptTx(tx)).Exec("CREATE TABLE IF NOT EXISTS soft_delete_test (id varchar(255) primary key, name varchar(255), deleted_utc timestamp)"))
+}
+
+func TestConnectionSoftDelete(t *testing.T) {
+	assert := assert.New(t)
+	tx, err := defaultDB().Begin()
+	assert.Nil(err)
+	defer tx.Rollback()
+
+	assert.Nil(createSoftDeleteTestTable(tx))
+
+	obj := &softDeleteTest{ID: uuid.V4().String(), Name: "one"}
+	assert.Nil(defaultDB().Invoke(OptTx(tx)).Create(obj))
+
+	deleted, err := defaultDB().Invoke(OptTx(tx)).Delete(obj)
+	assert.Nil(err)
+	assert.True(deleted)
+	assert.NotNil(obj.DeletedUTC)
+
+	deleted, err = defaultDB().Invoke(OptTx(tx)).Delete(obj)
+	assert.Nil(err)
+	assert.False(deleted, "already soft deleted rows should not be deleted again")
+
+	var verify softDeleteTest
+	found, err := defaultDB().Invoke(OptTx(tx)).Get(&verify, obj.ID)
+	assert.Nil(err)
+	assert.False(found)
+
+	exists, err := defaultDB().Invoke(OptTx(tx)).Exists(obj)
+	assert.Nil(err)
+	assert.False(exists)
+
+	found, err = defaultDB().Invoke(OptTx(tx), OptUnscoped()).Get(&verify, obj.ID)
+	assert.Nil(err)
+	assert.True(found)
+	assert.NotNil(verify.DeletedUTC)
+
+	restored, err := defaultDB().Invoke(OptTx(tx)).Restore(obj)
+	assert.Nil(err)
+	assert.True(restored)
+	assert.Nil(obj.DeletedUTC)
+
+	exists, err = defaultDB().Invoke(OptTx(tx)).Exists(obj)
+	assert.Nil(err)
+	assert.True(exists)
+
+	deleted, err = defaultDB().Invoke(OptTx(tx)).HardDelete(obj)
+	assert.Nil(err)
+	assert.True(deleted)
+
+	exists, err = defaultDB().Invoke(OptTx(tx), OptUnscoped()).Exists(obj)
+	assert.Nil(err)
+	assert.False(exists)
+}
@@ -0,0 +1,258 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/blend/go-sdk/async"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+)
+
+// Notify publishes a notification with a payload on a channel, with `pg_notify`.
+func (dbc *Connection) Notify(ctx context.Context, channel, payload string) error {
+	return IgnoreExecResult(dbc.Invoke(OptContext(ctx), OptLabel("notify")).Exec("SELECT pg_notify($1, $2)", channel, payload))
+}
+
+// NewListener returns a new listener for notifications, which holds a dedicated connection built from a config.
+//
+//	listener := db.NewListener(cfg, db.OptListenerLog(log))
+//	listener.Listen("cache_invalidations", func(ctx context.Context, n db.Notification) {
+//		cache.Remove(n.Payload)
+//	})
+//	go listener.Start()
+//	<-listener.NotifyStarted()
+func NewListener(cfg Config, options ...ListenerOption) *Listener {
+	l := Listener{
+		Latch:                async.NewLatch(),
+		Config:               cfg,
+		MinReconnectInterval: DefaultListenerMinReconnectInterval,
+		MaxReconnectInterval: DefaultListenerMaxReconnectInterval,
+		PingInterval:         DefaultListenerPingInterval,
+		handlers:             map[string][]NotificationHandler{},
+	}
+	for _, option := range options {
+		option(&l)
+	}
+	return &l
+}
+
+// ListenerOption is an option for a listener.
+type ListenerOption func(*Listener)
+
+// OptListenerLog sets the listener logger, which connection events are logged to.
+func OptListenerLog(log logger.Log) ListenerOption {
+	return func(l *Listener) {
+		l.Log = log
+	}
+}
+
+// OptListenerReconnectInterval sets the minimum and maximum delays between reconnect attempts.
+// The delay starts at the minimum and doubles after each failed attempt, up to the maximum.
+func OptListenerReconnectInterval(min, max time.Duration) ListenerOption {
+	return func(l *Listener) {
+		l.MinReconnectInterval = min
+		l.MaxReconnectInterval = max
+	}
+}
+
+// OptListenerPingInterval sets the interval the listener pings its connection on to detect connection loss.
+func OptListenerPingInterval(d time.Duration) ListenerOption {
+	return func(l *Listener) {
+		l.PingInterval = d
+	}
+}
+
+// Notification is a notification received on a channel.
+type Notification struct {
+	Channel    string
+	Payload    string
+	BackendPID int
+}
+
+// NotificationHandler handles notifications.
+type NotificationHandler func(context.Context, Notification)
+
+// Listener delivers notifications on the channels it listens to to handlers.
+//
+// If its connection is lost it reconnects, with backoff, and listens to its channels again;
+// notifications sent while it is disconnected are lost.
+type Listener struct {
+	*async.Latch
+	Config               Config
+	Log                  logger.Log
+	MinReconnectInterval time.Duration
+	MaxReconnectInterval time.Duration
+	PingInterval         time.Duration
+
+	sync.Mutex
+	listener *pq.Listener
+	handlers map[string][]NotificationHandler
+}
+
+// Listen adds a handler for notifications on a channel.
+// Handlers are called in order, on the listener's goroutine, so they should not block.
+func (l *Listener) Listen(channel string, handler NotificationHandler) error {
+	l.Lock()
+	l.handlers[channel] = append(l.handlers[channel], handler)
+	listener, isNew := l.listener, len(l.handlers[channel]) == 1
+	l.Unlock()
+
+	if listener != nil && isNew {
+		return l.listen(listener, channel)
+	}
+	return nil
+}
+
+// ListenChan sends notifications on a channel to a go channel.
+// Sends block the listener until they're received, or the listener is stopped.
+func (l *Listener) ListenChan(channel string, notifications chan<- Notification) error {
+	return l.Listen(channel, func(ctx context.Context, n Notification) {
+		select {
+		case notifications <- n:
+		case <-l.NotifyStopping():
+		}
+	})
+}
+
+// Unlisten removes the handlers for a channel, and stops listening to it.
+func (l *Listener) Unlisten(channel string) error {
+	l.Lock()
+	_, hadHandlers := l.handlers[channel]
+	delete(l.handlers, channel)
+	listener := l.listener
+	l.Unlock()
+
+	if listener != nil && hadHandlers {
+		if err := listener.Unlisten(channel); err != nil && err != pq.ErrChannelNotOpen {
+			return Error(err)
+		}
+	}
+	return nil
+}
+
+// Start connects the listener, listens to its channels and delivers notifications until the listener is stopped.
+// Errors listening to channels are logged.
+//
+// This call will block.
+func (l *Listener) Start() error {
+	if !l.CanStart() {
+		return ex.New(async.ErrCannotStart)
+	}
+	l.Starting()
+
+	listener := pq.NewListener(l.Config.CreateDSN(), l.MinReconnectInterval, l.MaxReconnectInterval, l.event)
+	l.Lock()
+	l.listener = listener
+	var channels []string
+	for channel := range l.handlers {
+		channels = append(channels, channel)
+	}
+	l.Unlock()
+
+	// listening blocks until the listener is connected, so it's done in the background
+	// so that the listener can be stopped in the meantime.
+	go func() {
+		for _, channel := range channels {
+			if err := l.listen(listener, channel); err != nil {
+				logger.MaybeError(l.Log, err)
+			}
+		}
+	}()
+	l.dispatch(listener)
+	return nil
+}
+
+// Stop stops the listener and closes its connection.
+func (l *Listener) Stop() error {
+	if !l.CanStop() {
+		return ex.New(async.ErrCannotStop)
+	}
+	l.Stopping()
+	<-l.NotifyStopped()
+	return nil
+}
+
+// --------------------------------------------------------------------------------
+// helpers
+// --------------------------------------------------------------------------------
+
+func (l *Listener) dispatch(listener *pq.Listener) {
+	l.Started()
+	ping := time.NewTicker(l.PingInterval)
+	defer ping.Stop()
+
+	ctx := context.Background()
+	stopping := l.NotifyStopping()
+	for {
+		select {
+		case n, ok := <-listener.Notify:
+			if !ok {
+				l.close()
+				l.Stopped()
+				return
+			}
+			// a nil notification is sent after the connection is re-established,
+			// as notifications may have been missed while it was lost.
+			if n == nil {
+				continue
+			}
+			l.deliver(ctx, Notification{Channel: n.Channel, Payload: n.Extra, BackendPID: n.BePid})
+		case <-ping.C:
+			go func() {
+				if err := listener.Ping(); err != nil {
+					logger.MaybeWarningf(l.Log, "db listener: ping failed: %v", err)
+				}
+			}()
+		case <-stopping:
+			l.close()
+			l.Stopped()
+			return
+		}
+	}
+}
+
+func (l *Listener) deliver(ctx context.Context, n Notification) {
+	l.Lock()
+	handlers := l.handlers[n.Channel]
+	l.Unlock()
+	for _, handler := range handlers {
+		handler(ctx, n)
+	}
+}
+
+func (l *Listener) listen(listener *pq.Listener, channel string) error {
+	if err := listener.Listen(channel); err != nil && err != pq.ErrChannelAlreadyOpen {
+		return Error(err, ex.OptMessagef("channel: %s", channel))
+	}
+	return nil
+}
+
+func (l *Listener) close() {
+	l.Lock()
+	listener := l.listener
+	l.listener = nil
+	l.Unlock()
+	if listener != nil {
+		if err := listener.Close(); err != nil {
+			logger.MaybeError(l.Log, err)
+		}
+	}
+}
+
+// event logs listener connection events.
+func (l *Listener) event(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventConnected:
+		logger.MaybeInfof(l.Log, "db listener: connected")
+	case pq.ListenerEventDisconnected:
+		logger.MaybeWarningf(l.Log, "db listener: disconnected: %v", err)
+	case pq.ListenerEventReconnected:
+		logger.MaybeInfof(l.Log, "db listener: reconnected")
+	case pq.ListenerEventConnectionAttemptFailed:
+		logger.MaybeErrorf(l.Log, "db listener: connection attempt failed: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+)
+
+func TestNewListener(t *testing.T) {
+	assert := assert.New(t)
+
+	listener := NewListener(Config{Database: "foo"})
+	assert.Equal("foo", listener.Config.Database)
+	assert.Equal(DefaultListenerMinReconnectInterval, listener.MinReconnectInterval)
+	assert.Equal(DefaultListenerMaxReconnectInterval, listener.MaxReconnectInterval)
+	assert.Equal(DefaultListenerPingInterval, listener.PingInterval)
+
+	listener = NewListener(Config{},
+		OptListenerReconnectInterval(time.Second, 2*time.Second),
+		OptListenerPingInterval(3*time.Second),
+	)
+	assert.Equal(time.Second, listener.MinReconnectInterval)
+	assert.Equal(2*time.Second, listener.MaxReconnectInterval)
+	assert.Equal(3*time.Second, listener.PingInterval)
+}
+
+func TestListenerHandlers(t *testing.T) {
+	assert := assert.New(t)
+
+	listener := NewListener(Config{})
+
+	var payloads []string
+	assert.Nil(listener.Listen("foo", func(_ context.Context, n Notification) {
+		payloads = append(payloads, "first "+n.Payload)
+	}))
+	assert.Nil(listener.Listen("foo", func(_ context.Context, n Notification) {
+		payloads = append(payloads, "second "+n.Payload)
+	}))
+
+	listener.deliver(context.Background(), Notification{Channel: "foo", Payload: "one"})
+	listener.deliver(context.Background(), Notification{Channel: "bar", Payload: "two"})
+	assert.Equal([]string{"first one", "second one"}, payloads)
+
+	assert.Nil(listener.Unlisten("foo"))
+	listener.deliver(context.Background(), Notification{Channel: "foo", Payload: "three"})
+	assert.Len(payloads, 2)
+}
+
+func TestListenerNotify(t *testing.T) {
+	assert := assert.New(t)
+
+	listener := NewListener(defaultDB().Config, OptListenerLog(defaultDB().Log))
+	channel := "listener_test_" + uuid.V4().String()
+	notifications := make(chan Notification, 1)
+	assert.Nil(listener.ListenChan(channel, notifications))
+
+	go listener.Start()
+	<-listener.NotifyStarted()
+	defer listener.Stop()
+
+	// wait for the listener to connect and listen.
+	deadline := time.After(5 * time.Second)
+	for {
+		assert.Nil(defaultDB().Notify(context.Background(), channel, "payload"))
+		select {
+		case n := <-notifications:
+			assert.Equal(channel, n.Channel)
+			assert.Equal("payload", n.Payload)
+			return
+		case <-time.After(100 * time.Millisecond):
+		case <-deadline:
+			assert.FailNow("should have received a notification")
+		}
+	}
+}
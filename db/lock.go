@@ -0,0 +1,199 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// Lock acquires a postgres advisory lock for a name, blocking until it is acquired or the context is done.
+//
+// By default the lock is session scoped; a connection is held for the lifetime of the lock, and
+// the lock must be released with `Unlock`. If the connection is lost, the lock is lost with it,
+// which is signalled by closing the `Lost` channel.
+//
+//	lock, err := db.Lock(ctx, conn, "report_generation")
+//	if err != nil {
+//		return err
+//	}
+//	defer lock.Unlock(ctx)
+//
+// With `OptLockTx` the lock is transaction scoped instead, and is released when the transaction ends.
+func Lock(ctx context.Context, conn *Connection, name string, options ...LockOption) (*AdvisoryLock, error) {
+	lock, _, err := acquireLock(ctx, conn, name, false, options...)
+	return lock, err
+}
+
+// TryLock acquires a postgres advisory lock for a name if it is not held elsewhere, without blocking.
+// It returns whether the lock was acquired; if not, the lock returned is nil.
+//
+// See `Lock` for the lock scopes.
+func TryLock(ctx context.Context, conn *Connection, name string, options ...LockOption) (*AdvisoryLock, bool, error) {
+	return acquireLock(ctx, conn, name, true, options...)
+}
+
+// LockKey returns the advisory lock key for a name.
+func LockKey(name string) int64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(name))
+	return int64(hash.Sum64())
+}
+
+// LockOptions are options for advisory locks.
+type LockOptions struct {
+	Tx            *sql.Tx
+	CheckInterval time.Duration
+}
+
+// LockOption mutates lock options.
+type LockOption func(*LockOptions)
+
+// OptLockTx makes a lock transaction scoped, acquiring it in a transaction.
+// It is released when the transaction commits or rolls back.
+func OptLockTx(tx *sql.Tx) LockOption {
+	return func(lo *LockOptions) {
+		lo.Tx = tx
+	}
+}
+
+// OptLockCheckInterval sets the interval a session scoped lock's connection is checked on.
+// An interval of zero or less disables the check, in which case the `Lost` channel is never closed.
+func OptLockCheckInterval(d time.Duration) LockOption {
+	return func(lo *LockOptions) {
+		lo.CheckInterval = d
+	}
+}
+
+// AdvisoryLock is a held postgres advisory lock.
+type AdvisoryLock struct {
+	Name string
+	Key  int64
+
+	conn    *sql.Conn
+	lost    chan struct{}
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// Lost returns a channel that is closed if the connection a session scoped lock is held on is lost.
+// It returns nil for transaction scoped locks.
+func (al *AdvisoryLock) Lost() <-chan struct{} {
+	return al.lost
+}
+
+// Unlock releases a session scoped lock and the connection it is held on.
+// Transaction scoped locks are released when their transaction ends, so this does nothing for them.
+func (al *AdvisoryLock) Unlock(ctx context.Context) (err error) {
+	if al.conn == nil {
+		return
+	}
+	close(al.stop)
+	<-al.stopped
+
+	defer func() {
+		err = ex.Nest(err, Error(al.conn.Close()))
+		al.conn = nil
+	}()
+	var unlocked bool
+	if err = al.conn.QueryRowContext(ctx, "SELECT pg_advisory_unlock($1)", al.Key).Scan(&unlocked); err != nil {
+		err = Error(err)
+		return
+	}
+	if !unlocked {
+		err = Error(ErrLockNotHeld, ex.OptMessagef("name: %s", al.Name))
+	}
+	return
+}
+
+// --------------------------------------------------------------------------------
+// helpers
+// --------------------------------------------------------------------------------
+
+func acquireLock(ctx context.Context, conn *Connection, name string, try bool, options ...LockOption) (lock *AdvisoryLock, acquired bool, err error) {
+	lockOptions := LockOptions{
+		CheckInterval: DefaultLockCheckInterval,
+	}
+	for _, option := range options {
+		option(&lockOptions)
+	}
+	al := &AdvisoryLock{
+		Name: name,
+		Key:  LockKey(name),
+	}
+
+	var db DB
+	function := "pg_advisory_lock"
+	if lockOptions.Tx != nil {
+		db = lockOptions.Tx
+		function = "pg_advisory_xact_lock"
+	} else {
+		if conn == nil || conn.Connection == nil {
+			err = Error(ErrConnectionClosed)
+			return
+		}
+		if al.conn, err = conn.Connection.Conn(ctx); err != nil {
+			err = Error(err)
+			return
+		}
+		db = al.conn
+		defer func() {
+			if !acquired {
+				err = ex.Nest(err, Error(al.conn.Close()))
+			}
+		}()
+	}
+	if try {
+		if err = db.QueryRowContext(ctx, "SELECT pg_try_"+strings.TrimPrefix(function, "pg_")+"($1)", al.Key).Scan(&acquired); err != nil {
+			err = Error(err)
+			return
+		}
+	} else {
+		if _, err = db.ExecContext(ctx, "SELECT "+function+"($1)", al.Key); err != nil {
+			err = Error(err)
+			return
+		}
+		acquired = true
+	}
+	if !acquired {
+		return
+	}
+	if al.conn != nil {
+		al.start(lockOptions.CheckInterval)
+	}
+	lock = al
+	return
+}
+
+// start starts checking the connection a session scoped lock is held on, if the interval is positive.
+func (al *AdvisoryLock) start(interval time.Duration) {
+	al.lost = make(chan struct{})
+	al.stop = make(chan struct{})
+	al.stopped = make(chan struct{})
+	if interval <= 0 {
+		close(al.stopped)
+		return
+	}
+	go al.check(interval)
+}
+
+// check pings the connection a session scoped lock is held on, closing the lost channel if it fails.
+func (al *AdvisoryLock) check(interval time.Duration) {
+	defer close(al.stopped)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := al.conn.PingContext(context.Background()); err != nil {
+				close(al.lost)
+				return
+			}
+		case <-al.stop:
+			return
+		}
+	}
+}
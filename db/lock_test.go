@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+)
+
+func TestLockKey(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(LockKey("foo"), LockKey("foo"))
+	assert.NotEqual(LockKey("foo"), LockKey("bar"))
+}
+
+func TestLockOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	var lo LockOptions
+	OptLockCheckInterval(time.Second)(&lo)
+	assert.Equal(time.Second, lo.CheckInterval)
+	assert.Nil(lo.Tx)
+}
+
+func TestAdvisoryLockStartNoCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	lock := &AdvisoryLock{Name: "foo", Key: LockKey("foo")}
+	lock.start(0)
+	assert.NotNil(lock.Lost())
+
+	close(lock.stop)
+	select {
+	case <-lock.stopped:
+	case <-time.After(time.Second):
+		assert.FailNow("stopping a lock without a check should not block")
+	}
+	select {
+	case <-lock.Lost():
+		assert.FailNow("a lock without a check should not be lost")
+	default:
+	}
+}
+
+func TestLockConnectionClosed(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := Lock(context.Background(), &Connection{}, "foo")
+	assert.True(IsConnectionClosed(err))
+	_, acquired, err := TryLock(context.Background(), nil, "foo")
+	assert.True(IsConnectionClosed(err))
+	assert.False(acquired)
+}
+
+func TestAdvisoryLockUnlockTx(t *testing.T) {
+	assert := assert.New(t)
+
+	lock := &AdvisoryLock{Name: "foo", Key: LockKey("foo")}
+	assert.Nil(lock.Lost())
+	assert.Nil(lock.Unlock(context.Background()))
+}
+
+func TestLockSession(t *testing.T) {
+	assert := assert.New(t)
+
+	name := "lock_test_" + uuid.V4().String()
+	lock, err := Lock(context.Background(), defaultDB(), name, OptLockCheckInterval(10*time.Millisecond))
+	assert.Nil(err)
+	assert.NotNil(lock.Lost())
+
+	other, acquired, err := TryLock(context.Background(), defaultDB(), name)
+	assert.Nil(err)
+	assert.False(acquired)
+	assert.Nil(other)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = Lock(ctx, defaultDB(), name)
+	assert.NotNil(err, "a blocking lock should wait until the context is done")
+
+	assert.Nil(lock.Unlock(context.Background()))
+	assert.Nil(lock.Unlock(context.Background()))
+
+	other, acquired, err = TryLock(context.Background(), defaultDB(), name)
+	assert.Nil(err)
+	assert.True(acquired)
+	assert.Nil(other.Unlock(context.Background()))
+}
+
+func TestLockSessionNoCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	name := "lock_test_" + uuid.V4().String()
+	lock, err := Lock(context.Background(), defaultDB(), name, OptLockCheckInterval(0))
+	assert.Nil(err)
+	assert.NotNil(lock.Lost())
+	assert.Nil(lock.Unlock(context.Background()))
+}
+
+func TestLockTx(t *testing.T) {
+	assert := assert.New(t)
+
+	name := "lock_test_" + uuid.V4().String()
+	tx, err := defaultDB().Begin()
+	assert.Nil(err)
+
+	lock, acquired, err := TryLock(context.Background(), defaultDB(), name, OptLockTx(tx))
+	assert.Nil(err)
+	assert.True(acquired)
+	assert.Nil(lock.Lost())
+
+	_, acquired, err = TryLock(context.Background(), defaultDB(), name)
+	assert.Nil(err)
+	assert.False(acquired)
+
+	assert.Nil(tx.Rollback())
+	other, acquired, err := TryLock(context.Background(), defaultDB(), name)
+	assert.Nil(err)
+	assert.True(acquired, "a transaction scoped lock should be released when the transaction ends")
+	assert.Nil(other.Unlock(context.Background()))
+}
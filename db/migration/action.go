@@ -15,12 +15,27 @@ type Actionable interface {
 // Action is a function that can be run during a migration step.
 type Action func(context.Context, *db.Connection, *sql.Tx) error
 
+// actionFunc is an actionable that runs an action.
+type actionFunc Action
+
+// Action implements Actionable.
+func (af actionFunc) Action(ctx context.Context, c *db.Connection, tx *sql.Tx) error {
+	return af(ctx, c, tx)
+}
+
 // NoOp performs no action.
 func NoOp(_ context.Context, _ *db.Connection, _ *sql.Tx) error { return nil }
 
 // Statements returns a body func that executes the statments serially.
+// If the context has a plan, the statements are captured to it instead.
 func Statements(statements ...string) Action {
 	return func(ctx context.Context, c *db.Connection, tx *sql.Tx) (err error) {
+		if plan := GetContextPlan(ctx); plan != nil {
+			for _, statement := range statements {
+				plan.capture(statement)
+			}
+			return
+		}
 		for _, statement := range statements {
 			err = db.IgnoreExecResult(c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Exec(statement))
 			if err != nil {
@@ -32,9 +47,14 @@ func Statements(statements ...string) Action {
 }
 
 // Exec creates an Action that will run a statement with a given set of arguments.
-// It can be used in lieu of Statements, when parameterization is needed
+// It can be used in lieu of Statements, when parameterization is needed.
+// If the context has a plan, the statement is captured to it instead.
 func Exec(statement string, args ...interface{}) Action {
 	return func(ctx context.Context, c *db.Connection, tx *sql.Tx) (err error) {
+		if plan := GetContextPlan(ctx); plan != nil {
+			plan.capture(statement, args...)
+			return
+		}
 		err = db.IgnoreExecResult(c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Exec(statement, args...))
 		return
 	}
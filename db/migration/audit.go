@@ -0,0 +1,22 @@
+package migration
+
+import (
+	"github.com/blend/go-sdk/db"
+)
+
+// AuditTable returns a migration group that creates the table the `db.AuditTable` sink inserts
+// audit records into, if it doesn't exist. The table defaults to `db.DefaultAuditTable`.
+func AuditTable(table string, options ...GroupOption) *Group {
+	if table == "" {
+		table = db.DefaultAuditTable
+	}
+	return NewGroupWithAction(TableNotExists(table), Statements(AuditTableStatements(table)...), options...)
+}
+
+// AuditTableStatements returns the statements that create an audit table.
+func AuditTableStatements(table string) []string {
+	return []string{
+		"CREATE TABLE " + table + " (id bigserial primary key, table_name text not null, action text not null, key jsonb not null, actor text not null, changes jsonb not null, timestamp_utc timestamp not null)",
+		"CREATE INDEX ix_" + table + "_table_name_key ON " + table + " (table_name, key)",
+	}
+}
@@ -0,0 +1,49 @@
+package migration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/db"
+)
+
+func TestAuditTableStatements(t *testing.T) {
+	assert := assert.New(t)
+
+	statements := AuditTableStatements("test_audit")
+	assert.Len(statements, 2)
+	assert.True(strings.HasPrefix(statements[0], "CREATE TABLE test_audit ("))
+	assert.Equal("CREATE INDEX ix_test_audit_table_name_key ON test_audit (table_name, key)", statements[1])
+}
+
+type auditTableTest struct {
+	ID    int    `db:"id,pk"`
+	Email string `db:"email,audit"`
+}
+
+func (auditTableTest) TableName() string { return "audit_table_test" }
+
+func TestAuditTable(t *testing.T) {
+	assert := assert.New(t)
+	tx, err := defaultDB().Begin()
+	assert.Nil(err)
+	defer tx.Rollback()
+
+	assert.Nil(AuditTable("test_audit", OptGroupTx(tx)).Action(context.Background(), defaultDB()))
+	assert.Nil(db.IgnoreExecResult(defaultDB().Invoke(db.OptTx(tx)).Exec("CREATE TABLE audit_table_test (id int primary key, email text not null)")))
+
+	invoke := func() *db.Invocation {
+		return defaultDB().Invoke(db.OptTx(tx), db.OptContext(db.WithAuditActor(context.Background(), "test")), db.OptAudit(db.NewAuditTable("test_audit")))
+	}
+	object := auditTableTest{ID: 1, Email: "foo@example.com"}
+	assert.Nil(invoke().Create(&object))
+	object.Email = "bar@example.com"
+	_, err = invoke().Update(&object)
+	assert.Nil(err)
+
+	var count int
+	assert.Nil(defaultDB().Invoke(db.OptTx(tx)).Query("SELECT count(*) FROM test_audit WHERE table_name = $1 AND actor = $2", "audit_table_test", "test").Scan(&count))
+	assert.Equal(2, count)
+}
@@ -2,8 +2,36 @@ package migration
 
 // Migration Stats
 const (
-	StatApplied = "applied"
-	StatFailed  = "failed"
-	StatSkipped = "skipped"
-	StatTotal   = "total"
+	StatApplied    = "applied"
+	StatFailed     = "failed"
+	StatSkipped    = "skipped"
+	StatRolledBack = "rolledback"
+	StatTotal      = "total"
+)
+
+// Plan results.
+const (
+	// PlanWouldApply is the result of a planned step that would be applied.
+	PlanWouldApply = "would apply"
+	// PlanWouldSkip is the result of a planned step that would be skipped.
+	PlanWouldSkip = "would skip"
+)
+
+// Migration statuses.
+const (
+	// StatusApplied is the status of a migration that has been applied.
+	StatusApplied = "applied"
+	// StatusPending is the status of a migration that hasn't been applied.
+	StatusPending = "pending"
+	// StatusModified is the status of an applied migration whose sql has changed since it was applied.
+	StatusModified = "modified"
+	// StatusOutOfOrder is the status of a pending migration that precedes an applied migration.
+	StatusOutOfOrder = "outoforder"
+	// StatusMissing is the status of an applied migration that isn't in the suite.
+	StatusMissing = "missing"
+)
+
+const (
+	// DefaultTrackingTable is the default table applied migrations are recorded in.
+	DefaultTrackingTable = "schema_migrations"
 )
@@ -33,3 +33,18 @@ func GetContextLabels(ctx context.Context) []string {
 	}
 	return nil
 }
+
+type stepsRunKey struct{}
+
+// withStepsRun returns a context that steps record if their guards ran them to.
+func withStepsRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, stepsRunKey{}, map[*Step]bool{})
+}
+
+// getContextStepsRun gets the steps that have run from a context, or nil if they aren't recorded.
+func getContextStepsRun(ctx context.Context) map[*Step]bool {
+	if typed, ok := ctx.Value(stepsRunKey{}).(map[*Step]bool); ok {
+		return typed
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package migration
+
+import "github.com/blend/go-sdk/ex"
+
+// Errors
+const (
+	// ErrMigrationDuplicate is returned if more than one group of a suite has the same id.
+	ErrMigrationDuplicate ex.Class = "migration: duplicate migration id"
+	// ErrMigrationModified is returned if the checksum of an applied migration's sql doesn't match the checksum it was applied with.
+	ErrMigrationModified ex.Class = "migration: applied migration was modified"
+	// ErrMigrationOutOfOrder is returned if a pending migration precedes an applied migration.
+	ErrMigrationOutOfOrder ex.Class = "migration: pending migration precedes an applied migration"
+	// ErrMigrationNotFound is returned if a rollback's version isn't a migration of the suite.
+	ErrMigrationNotFound ex.Class = "migration: migration not found"
+	// ErrMigrationIrreversible is returned if a rollback would roll back a migration without down actions.
+	ErrMigrationIrreversible ex.Class = "migration: migration has no down actions"
+	// ErrSQLFileInvalid is returned if a migration sql file's name or headers are invalid.
+	ErrSQLFileInvalid ex.Class = "migration: invalid sql file"
+)
+
+// IsMigrationModified returns if an error is an `ErrMigrationModified`.
+func IsMigrationModified(err error) bool {
+	return ex.Is(err, ErrMigrationModified)
+}
+
+// IsMigrationIrreversible returns if an error is an `ErrMigrationIrreversible`.
+func IsMigrationIrreversible(err error) bool {
+	return ex.Is(err, ErrMigrationIrreversible)
+}
+
+// IsMigrationOutOfOrder returns if an error is an `ErrMigrationOutOfOrder`.
+func IsMigrationOutOfOrder(err error) bool {
+	return ex.Is(err, ErrMigrationOutOfOrder)
+}
+
+// IsSQLFileInvalid returns if an error is an `ErrSQLFileInvalid`.
+func IsSQLFileInvalid(err error) bool {
+	return ex.Is(err, ErrSQLFileInvalid)
+}
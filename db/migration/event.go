@@ -97,31 +97,46 @@ func NewStatsEvent(applied, skipped, failed, total int) *StatsEvent {
 
 // StatsEvent is a migration logger event.
 type StatsEvent struct {
-	applied int
-	skipped int
-	failed  int
-	total   int
+	applied    int
+	skipped    int
+	failed     int
+	rolledBack int
+	total      int
+}
+
+// WithRolledBack sets the number of rolled back steps.
+func (se *StatsEvent) WithRolledBack(rolledBack int) *StatsEvent {
+	se.rolledBack = rolledBack
+	return se
 }
 
 // GetFlag implements logger.Event.
 func (se StatsEvent) GetFlag() string { return FlagStats }
 
 // WriteText writes the event to a text writer.
+// Rolled back steps are only written if there are any.
 func (se StatsEvent) WriteText(tf logger.TextFormatter, wr io.Writer) {
-	io.WriteString(wr, fmt.Sprintf("%s applied %s skipped %s failed %s total",
+	io.WriteString(wr, fmt.Sprintf("%s applied %s skipped %s failed ",
 		tf.Colorize(fmt.Sprintf("%d", se.applied), ansi.ColorGreen),
 		tf.Colorize(fmt.Sprintf("%d", se.skipped), ansi.ColorLightGreen),
 		tf.Colorize(fmt.Sprintf("%d", se.failed), ansi.ColorRed),
-		tf.Colorize(fmt.Sprintf("%d", se.total), ansi.ColorLightWhite),
 	))
+	if se.rolledBack > 0 {
+		io.WriteString(wr, fmt.Sprintf("%s rolled back ", tf.Colorize(fmt.Sprintf("%d", se.rolledBack), ansi.ColorYellow)))
+	}
+	io.WriteString(wr, fmt.Sprintf("%s total", tf.Colorize(fmt.Sprintf("%d", se.total), ansi.ColorLightWhite)))
 }
 
 // Decompose implements logger.JSONWritable.
 func (se StatsEvent) Decompose() map[string]interface{} {
-	return map[string]interface{}{
+	decomposed := map[string]interface{}{
 		StatApplied: se.applied,
 		StatSkipped: se.skipped,
 		StatFailed:  se.failed,
 		StatTotal:   se.total,
 	}
+	if se.rolledBack > 0 {
+		decomposed[StatRolledBack] = se.rolledBack
+	}
+	return decomposed
 }
@@ -61,4 +61,11 @@ func TestStatsEvent(t *testing.T) {
 	a.Contains(json, `"skipped":2`)
 	a.Contains(json, `"failed":0`)
 	a.Contains(json, `"total":7`)
+	a.NotContains(json, StatRolledBack)
+
+	b.Reset()
+	se = NewStatsEvent(0, 1, 0, 3).WithRolledBack(2)
+	se.WriteText(logger.NewTextOutputFormatter(), &b)
+	a.Equal("\x1b[0;32m0\x1b[0m applied \x1b[0;92m1\x1b[0m skipped \x1b[0;31m0\x1b[0m failed \x1b[0;33m2\x1b[0m rolled back \x1b[0;97m3\x1b[0m total", b.String())
+	a.Equal(2, se.Decompose()[StatRolledBack])
 }
@@ -33,14 +33,65 @@ func NewGroupWithAction(guard GuardFunc, action Action, options ...GroupOption)
 // It uses normally transactions to apply these actions as an atomic unit, but this transaction can be bypassed by
 // setting the SkipTransaction flag to true. This allows the use of CONCURRENT index creation and other operations that
 // postgres will not allow within a transaction.
+//
+// Groups with an ID are versioned migrations; see `NewVersion`.
 type Group struct {
-	Actions         []Actionable
+	ID      string
+	SQL     []string
+	Actions []Actionable
+	// Down are the actions that undo the group when it's rolled back.
+	// If unset, the down actions of the group's steps are run in reverse order,
+	// except for the steps whose guards skipped them when the group was applied.
+	Down            []Actionable
 	Tx              *sql.Tx
 	SkipTransaction bool
 }
 
 // Action runs the groups actions within a transaction.
 func (ga *Group) Action(ctx context.Context, c *db.Connection) (err error) {
+	return ga.run(ctx, c, ga.Actions, nil)
+}
+
+// DownActions returns the actions that undo the group, or nil if the group can't be undone.
+func (ga *Group) DownActions() []Actionable {
+	return ga.downActions(nil)
+}
+
+// SkippedSteps returns the indexes of the group's steps that didn't run, given the steps that did.
+func (ga *Group) SkippedSteps(stepsRun map[*Step]bool) (skipped []int) {
+	for index, action := range ga.Actions {
+		if step, ok := action.(*Step); ok && !stepsRun[step] {
+			skipped = append(skipped, index)
+		}
+	}
+	return
+}
+
+// downActions returns the actions that undo the group, leaving out the down actions of steps that
+// were skipped when the group was applied, or nil if the group can't be undone.
+// Explicit down actions are always returned.
+func (ga *Group) downActions(skipped map[int]bool) []Actionable {
+	if len(ga.Down) > 0 {
+		return ga.Down
+	}
+	if len(ga.Actions) == 0 {
+		return nil
+	}
+	down := []Actionable{}
+	for index := len(ga.Actions) - 1; index >= 0; index-- {
+		step, ok := ga.Actions[index].(*Step)
+		if !ok || step.Down == nil {
+			return nil
+		}
+		if !skipped[index] {
+			down = append(down, actionFunc(step.Down))
+		}
+	}
+	return down
+}
+
+// run runs actions within the group's transaction, followed by an action that runs in the same transaction, if set.
+func (ga *Group) run(ctx context.Context, c *db.Connection, actions []Actionable, after Action) (err error) {
 	var tx *sql.Tx
 	if ga.Tx != nil { // if we have a transaction provided to us
 		tx = ga.Tx
@@ -62,12 +113,14 @@ func (ga *Group) Action(ctx context.Context, c *db.Connection) (err error) {
 		}()
 	}
 
-	for _, a := range ga.Actions {
+	for _, a := range actions {
 		err = a.Action(ctx, c, tx)
 		if err != nil {
 			return
 		}
 	}
-
+	if after != nil {
+		err = after(ctx, c, tx)
+	}
 	return
 }
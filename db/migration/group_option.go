@@ -17,6 +17,36 @@ func OptGroupActions(actions ...Actionable) GroupOption {
 	}
 }
 
+// OptGroupID sets the ID of a group, which makes it a versioned migration.
+func OptGroupID(id string) GroupOption {
+	return func(g *Group) {
+		g.ID = id
+	}
+}
+
+// OptGroupSQL adds an action to a group that executes sql statements serially.
+// The statements are included in the checksum of versioned migrations.
+func OptGroupSQL(statements ...string) GroupOption {
+	return func(g *Group) {
+		g.SQL = append(g.SQL, statements...)
+		g.Actions = append(g.Actions, sqlAction(statements))
+	}
+}
+
+// OptGroupDown adds actions that undo the group when it's rolled back.
+func OptGroupDown(actions ...Actionable) GroupOption {
+	return func(g *Group) {
+		g.Down = append(g.Down, actions...)
+	}
+}
+
+// OptGroupDownSQL adds an action that undoes the group when it's rolled back, that executes sql statements serially.
+func OptGroupDownSQL(statements ...string) GroupOption {
+	return func(g *Group) {
+		g.Down = append(g.Down, sqlAction(statements))
+	}
+}
+
 // OptGroupSkipTransaction will allow this group to be run outside of a transaction. Use this to concurrently create indices
 // and perform other actions that cannot be executed in a Tx
 func OptGroupSkipTransaction() GroupOption {
@@ -129,6 +129,6 @@ func SchemaNotExists(schemaName string) GuardFunc {
 }
 
 func predicateSchemaExists(c *db.Connection, tx *sql.Tx, schemaName string) (bool, error) {
-	return c.Invoke(db.OptTx(tx)).Query(`SELECT 1 FROM information_schema.schemata WHERE schema_name = $1`,
+	return c.Invoke(db.OptTx(tx), db.OptPrimary()).Query(`SELECT 1 FROM information_schema.schemata WHERE schema_name = $1`,
 		strings.ToLower(schemaName)).Any()
 }
@@ -15,13 +15,30 @@ func NewStep(guard GuardFunc, action Action) *Step {
 	}
 }
 
+// NewStepWithDown returns a new Step, given a GuardFunc, an Action, and an Action that undoes it
+// when its group is rolled back.
+func NewStepWithDown(guard GuardFunc, action, down Action) *Step {
+	return &Step{
+		Guard: guard,
+		Body:  action,
+		Down:  down,
+	}
+}
+
 // Step is a guarded action. The GuardFunc will decide whether to execute this Action
 type Step struct {
 	Guard GuardFunc
 	Body  Action
+	// Down undoes the action, and is optional.
+	Down Action
 }
 
 // Action implements the Actionable interface and runs the body if the provided guard passes.
 func (ga *Step) Action(ctx context.Context, c *db.Connection, tx *sql.Tx) error {
-	return ga.Guard(ctx, c, tx, ga.Body)
+	return ga.Guard(ctx, c, tx, func(ctx context.Context, c *db.Connection, tx *sql.Tx) error {
+		if stepsRun := getContextStepsRun(ctx); stepsRun != nil {
+			stepsRun[ga] = true
+		}
+		return ga.Body(ctx, c, tx)
+	})
 }
@@ -132,7 +132,7 @@ func Exists(c *db.Connection, tx *sql.Tx, selectStatement string) (bool, error)
 	if !stringutil.HasPrefixCaseless(selectStatement, "select") {
 		return false, fmt.Errorf("statement must be a `SELECT`")
 	}
-	return c.Invoke(db.OptTx(tx)).Query(selectStatement).Any()
+	return c.Invoke(db.OptTx(tx), db.OptPrimary()).Query(selectStatement).Any()
 }
 
 // NotExists returns if a statement doesnt have results.
@@ -140,5 +140,5 @@ func NotExists(c *db.Connection, tx *sql.Tx, selectStatement string) (bool, erro
 	if !stringutil.HasPrefixCaseless(selectStatement, "select") {
 		return false, fmt.Errorf("statement must be a `SELECT`")
 	}
-	return c.Invoke(db.OptTx(tx)).Query(selectStatement).None()
+	return c.Invoke(db.OptTx(tx), db.OptPrimary()).Query(selectStatement).None()
 }
@@ -0,0 +1,159 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/ex"
+)
+
+// Plan is the result of a dry run of a suite; the steps that would apply or be skipped, and the
+// sql they would execute.
+type Plan struct {
+	Steps []PlanStep `json:"steps"`
+
+	statements []PlanStatement
+}
+
+// PlanStep is a step of a plan.
+type PlanStep struct {
+	Result     string          `json:"result"`
+	Labels     []string        `json:"labels,omitempty"`
+	Body       string          `json:"body,omitempty"`
+	Statements []PlanStatement `json:"statements,omitempty"`
+}
+
+// PlanStatement is a statement a step would execute.
+type PlanStatement struct {
+	Statement string        `json:"statement"`
+	Args      []interface{} `json:"args,omitempty"`
+}
+
+// Plan does a dry run of the suite, and returns the plan of the steps that would apply or be skipped,
+// with the statements `Statements` and `Exec` actions would execute.
+//
+// Guards are evaluated in a read only transaction that is rolled back, and actions are run with statements
+// captured rather than executed; other actions run in the read only transaction, so they fail if they write.
+// As statements aren't executed, guards are evaluated against the database as it is before the suite is applied.
+// Planned steps are only recorded in the plan; they aren't counted in the suite's results or written to its logger.
+func (s *Suite) Plan(ctx context.Context, c *db.Connection) (plan *Plan, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = ex.New(r)
+		}
+	}()
+
+	plan = new(Plan)
+	ctx = WithPlan(ctx, plan)
+
+	var tx *sql.Tx
+	if tx, err = c.BeginContext(ctx, func(txOptions *sql.TxOptions) { txOptions.ReadOnly = true }); err != nil {
+		return
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var applied map[string]bool
+	if s.IsVersioned() {
+		var migrations []AppliedMigration
+		if migrations, err = s.applied(ctx, c, tx); err != nil {
+			return
+		}
+		if applied, err = s.verify(migrations); err != nil {
+			return
+		}
+	}
+
+	for _, group := range s.Groups {
+		planned := *group
+		planned.Tx = tx
+		if group.ID == "" {
+			if err = planned.Action(WithSuite(ctx, s), c); err != nil {
+				return
+			}
+			continue
+		}
+		groupCtx := WithLabel(WithSuite(ctx, s), group.ID)
+		if applied[group.ID] {
+			s.Skipf(groupCtx, "already applied")
+			continue
+		}
+		if err = planned.Action(groupCtx, c); err != nil {
+			return
+		}
+		s.Applyf(groupCtx, "pending")
+	}
+	return
+}
+
+// WriteText writes the plan as text; statements are written after the step that would execute them.
+func (p Plan) WriteText(wr io.Writer) error {
+	for _, step := range p.Steps {
+		header := "-- " + step.Result
+		if len(step.Labels) > 0 {
+			header += " " + strings.Join(step.Labels, " > ")
+		}
+		if step.Body != "" {
+			header += " -- " + step.Body
+		}
+		if _, err := io.WriteString(wr, header+"\n"); err != nil {
+			return ex.New(err)
+		}
+		for _, statement := range step.Statements {
+			if _, err := io.WriteString(wr, strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(statement.Statement), ";"))+";\n"); err != nil {
+				return ex.New(err)
+			}
+			if len(statement.Args) > 0 {
+				if _, err := fmt.Fprintf(wr, "-- args: %v\n", statement.Args); err != nil {
+					return ex.New(err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the plan as json.
+func (p Plan) WriteJSON(wr io.Writer) error {
+	encoder := json.NewEncoder(wr)
+	encoder.SetIndent("", "  ")
+	return ex.New(encoder.Encode(p))
+}
+
+type planKey struct{}
+
+// WithPlan returns a context that `Statements` and `Exec` actions capture their statements to a plan with,
+// rather than executing them.
+func WithPlan(ctx context.Context, plan *Plan) context.Context {
+	return context.WithValue(ctx, planKey{}, plan)
+}
+
+// GetContextPlan gets a plan from a context as a value.
+func GetContextPlan(ctx context.Context) *Plan {
+	if ctx == nil {
+		return nil
+	}
+	if typed, ok := ctx.Value(planKey{}).(*Plan); ok {
+		return typed
+	}
+	return nil
+}
+
+// --------------------------------------------------------------------------------
+// helpers
+// --------------------------------------------------------------------------------
+
+// capture captures a statement for the next step.
+func (p *Plan) capture(statement string, args ...interface{}) {
+	p.statements = append(p.statements, PlanStatement{Statement: statement, Args: args})
+}
+
+// step adds a step with the statements captured since the last step.
+func (p *Plan) step(result, body string, labels ...string) {
+	p.Steps = append(p.Steps, PlanStep{Result: result, Labels: labels, Body: body, Statements: p.statements})
+	p.statements = nil
+}
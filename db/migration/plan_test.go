@@ -0,0 +1,99 @@
+package migration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/logger"
+)
+
+func TestPlanCapture(t *testing.T) {
+	assert := assert.New(t)
+
+	plan := new(Plan)
+	ctx := WithLabel(WithPlan(context.Background(), plan), "test")
+	assert.True(GetContextPlan(ctx) == plan)
+	assert.Nil(GetContextPlan(context.Background()))
+
+	// actions capture their statements rather than executing them, so they don't need a connection.
+	assert.Nil(Statements("CREATE TABLE foo (id int);", "CREATE INDEX ix_foo_id ON foo (id)")(ctx, nil, nil))
+	assert.Nil(Exec("INSERT INTO foo (id) VALUES ($1)", 1)(ctx, nil, nil))
+	output := new(bytes.Buffer)
+	s := New(OptLog(logger.MustNew(logger.OptAll(), logger.OptOutput(output))))
+	s.Applyf(ctx, "always run")
+	s.Skipf(ctx, "if not exists run")
+
+	// planned steps aren't counted or written.
+	applied, skipped, failed, total := s.Results()
+	assert.Zero(applied + skipped + failed + total)
+	s.Write(context.Background(), StatApplied, "not planned")
+	assert.Contains(output.String(), "not planned")
+	assert.NotContains(output.String(), "always run")
+	assert.NotContains(output.String(), "if not exists run")
+
+	assert.Len(plan.Steps, 2)
+	assert.Equal(PlanWouldApply, plan.Steps[0].Result)
+	assert.Equal([]string{"test"}, plan.Steps[0].Labels)
+	assert.Equal("always run", plan.Steps[0].Body)
+	assert.Len(plan.Steps[0].Statements, 3)
+	assert.Equal(PlanStatement{Statement: "INSERT INTO foo (id) VALUES ($1)", Args: []interface{}{1}}, plan.Steps[0].Statements[2])
+	assert.Equal(PlanWouldSkip, plan.Steps[1].Result)
+	assert.Empty(plan.Steps[1].Statements)
+
+	text := new(bytes.Buffer)
+	assert.Nil(plan.WriteText(text))
+	assert.Equal(`-- would apply test -- always run
+CREATE TABLE foo (id int);
+CREATE INDEX ix_foo_id ON foo (id);
+INSERT INTO foo (id) VALUES ($1);
+-- args: [1]
+-- would skip test -- if not exists run
+`, text.String())
+
+	encoded := new(bytes.Buffer)
+	assert.Nil(plan.WriteJSON(encoded))
+	var decoded Plan
+	assert.Nil(json.Unmarshal(encoded.Bytes(), &decoded))
+	assert.Len(decoded.Steps, 2)
+	assert.Equal("CREATE TABLE foo (id int);", decoded.Steps[0].Statements[0].Statement)
+}
+
+func TestSuitePlan(t *testing.T) {
+	assert := assert.New(t)
+	testSchemaName := buildTestSchemaName()
+	assert.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("CREATE SCHEMA %s", testSchemaName))))
+	defer func() {
+		assert.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE;", testSchemaName))))
+	}()
+	assert.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("CREATE TABLE %s.existing (id int)", testSchemaName))))
+
+	s := New(OptLog(logger.None()), OptTrackingTable(testSchemaName+".schema_migrations"), OptGroups(
+		NewGroupWithAction(TableNotExistsInSchema(testSchemaName, "existing"), Statements(fmt.Sprintf("CREATE TABLE %s.existing (id int)", testSchemaName))),
+		NewGroupWithAction(TableNotExistsInSchema(testSchemaName, "foo"), Statements(fmt.Sprintf("CREATE TABLE %s.foo (id int)", testSchemaName))),
+		NewVersion("0001", fmt.Sprintf("CREATE TABLE %s.bar (id int)", testSchemaName)),
+	))
+	plan, err := s.Plan(context.Background(), defaultDB())
+	assert.Nil(err)
+	assert.Len(plan.Steps, 3)
+	assert.Equal(PlanWouldSkip, plan.Steps[0].Result)
+	assert.Equal(PlanWouldApply, plan.Steps[1].Result)
+	assert.Len(plan.Steps[1].Statements, 1)
+	assert.Equal([]string{"0001"}, plan.Steps[2].Labels)
+	assert.Len(plan.Steps[2].Statements, 1)
+	applied, skipped, _, total := s.Results()
+	assert.Zero(applied)
+	assert.Zero(skipped)
+	assert.Zero(total)
+
+	// nothing was written.
+	for _, table := range []string{"foo", "bar", "schema_migrations"} {
+		exists, err := PredicateTableExistsInSchema(defaultDB(), nil, testSchemaName, table)
+		assert.Nil(err)
+		assert.False(exists)
+	}
+}
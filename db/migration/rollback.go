@@ -0,0 +1,134 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/ex"
+)
+
+// RollbackOption is an option for rollbacks.
+type RollbackOption func(*RollbackOptions)
+
+// RollbackOptions are the options for rollbacks.
+type RollbackOptions struct {
+	Force bool
+}
+
+// OptRollbackForce rolls back migrations that have no down actions by only removing them from the tracking table.
+func OptRollbackForce() RollbackOption {
+	return func(ro *RollbackOptions) {
+		ro.Force = true
+	}
+}
+
+// Rollback undoes the applied versioned migrations that follow a version, in reverse order, removing
+// them from the tracking table in the same transaction as their down actions. If the version is empty,
+// all applied migrations are rolled back.
+//
+// Rollback refuses to roll back migrations without down actions, unless forced. The down actions of steps
+// whose guards skipped them when they were applied aren't run. Applied migrations that aren't in the suite,
+// and groups without an ID, are not rolled back.
+func (s *Suite) Rollback(ctx context.Context, c *db.Connection, toVersion string, options ...RollbackOption) (err error) {
+	var rollbackOptions RollbackOptions
+	for _, option := range options {
+		option(&rollbackOptions)
+	}
+
+	defer s.WriteStats(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			err = ex.New(r)
+		}
+	}()
+
+	var lock *db.AdvisoryLock
+	if lock, err = db.Lock(ctx, c, s.TrackingTableOrDefault()); err != nil {
+		return
+	}
+	defer func() { err = ex.Nest(err, lock.Unlock(ctx)) }()
+
+	var migrations []AppliedMigration
+	if migrations, err = s.applied(ctx, c, nil); err != nil {
+		return
+	}
+	var groups []*Group
+	if groups, err = s.rollbackGroups(migrations, toVersion, rollbackOptions); err != nil {
+		return
+	}
+	appliedByID := make(map[string]AppliedMigration, len(migrations))
+	for _, migration := range migrations {
+		appliedByID[migration.ID] = migration
+	}
+
+	for _, group := range groups {
+		groupCtx := WithLabel(WithSuite(ctx, s), group.ID)
+		down := group.downActions(appliedByID[group.ID].Skipped())
+		if down == nil {
+			s.Skipf(groupCtx, "no down actions; removing from %s", s.TrackingTableOrDefault())
+		}
+		if err = group.run(groupCtx, c, down, s.recordRolledBack(group)); err != nil {
+			return s.Error(groupCtx, err)
+		}
+		if down != nil {
+			s.RolledBackf(groupCtx, "rolled back")
+		}
+	}
+	return
+}
+
+// RolledBackf writes a rolled back step message.
+func (s *Suite) RolledBackf(ctx context.Context, format string, args ...interface{}) {
+	s.RolledBack = s.RolledBack + 1
+	s.Total = s.Total + 1
+	s.Write(ctx, StatRolledBack, fmt.Sprintf(format, args...))
+}
+
+// --------------------------------------------------------------------------------
+// helpers
+// --------------------------------------------------------------------------------
+
+// rollbackGroups returns the applied groups that follow a version, in the order they should be rolled back.
+func (s *Suite) rollbackGroups(applied []AppliedMigration, toVersion string, options RollbackOptions) ([]*Group, error) {
+	statuses, err := s.statuses(applied)
+	if err != nil {
+		return nil, err
+	}
+	byID := map[string]*Group{}
+	for _, group := range s.Groups {
+		if group.ID != "" {
+			byID[group.ID] = group
+		}
+	}
+	if _, ok := byID[toVersion]; toVersion != "" && !ok {
+		return nil, ex.New(ErrMigrationNotFound, ex.OptMessagef("id: %s", toVersion))
+	}
+
+	var groups []*Group
+	for index := len(statuses) - 1; index >= 0; index-- {
+		status := statuses[index]
+		if status.ID == toVersion {
+			break
+		}
+		if status.Status != StatusApplied && status.Status != StatusModified {
+			continue
+		}
+		group := byID[status.ID]
+		if group.DownActions() == nil && !options.Force {
+			return nil, ex.New(ErrMigrationIrreversible, ex.OptMessagef("id: %s", group.ID))
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// recordRolledBack returns an action that removes a migration from the tracking table.
+func (s *Suite) recordRolledBack(group *Group) Action {
+	return func(ctx context.Context, c *db.Connection, tx *sql.Tx) error {
+		return db.IgnoreExecResult(c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Exec(
+			"DELETE FROM "+s.TrackingTableOrDefault()+" WHERE id = $1", group.ID,
+		))
+	}
+}
@@ -0,0 +1,182 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+)
+
+func TestGroupDownActions(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(NewVersion("0001", "CREATE TABLE foo (id int)").DownActions())
+	assert.Len(NewGroup(OptGroupID("0001"), OptGroupSQL("CREATE TABLE foo (id int)"), OptGroupDownSQL("DROP TABLE foo")).DownActions(), 1)
+
+	var ran []string
+	record := func(name string) Action {
+		return func(_ context.Context, _ *db.Connection, _ *sql.Tx) error {
+			ran = append(ran, name)
+			return nil
+		}
+	}
+	steps := NewGroupWithActions(
+		NewStepWithDown(Always(), record("up first"), record("down first")),
+		NewStepWithDown(Always(), record("up second"), record("down second")),
+	)
+	down := steps.DownActions()
+	assert.Len(down, 2)
+	for _, action := range down {
+		assert.Nil(action.Action(context.Background(), nil, nil))
+	}
+	assert.Equal([]string{"down second", "down first"}, ran)
+
+	assert.Nil(NewGroupWithActions(NewStepWithDown(Always(), NoOp, NoOp), NewStep(Always(), NoOp)).DownActions())
+}
+
+func TestGroupDownActionsSkippedSteps(t *testing.T) {
+	assert := assert.New(t)
+
+	var ran []string
+	record := func(name string) Action {
+		return func(_ context.Context, _ *db.Connection, _ *sql.Tx) error {
+			ran = append(ran, name)
+			return nil
+		}
+	}
+	skip := Guard("never run", func(_ *db.Connection, _ *sql.Tx) (bool, error) { return false, nil })
+	group := NewGroupWithActions(
+		NewStepWithDown(skip, record("up first"), record("down first")),
+		NewStepWithDown(Always(), record("up second"), record("down second")),
+	)
+
+	ctx := withStepsRun(context.Background())
+	for _, action := range group.Actions {
+		assert.Nil(action.Action(ctx, nil, nil))
+	}
+	assert.Equal([]string{"up second"}, ran)
+	assert.Equal([]int{0}, group.SkippedSteps(getContextStepsRun(ctx)))
+
+	ran = nil
+	down := group.downActions(AppliedMigration{SkippedSteps: "0"}.Skipped())
+	assert.Len(down, 1)
+	for _, action := range down {
+		assert.Nil(action.Action(context.Background(), nil, nil))
+	}
+	assert.Equal([]string{"down second"}, ran, "the down action of a skipped step should not run")
+
+	assert.Empty(AppliedMigration{}.Skipped())
+	assert.Equal(map[int]bool{0: true, 2: true}, AppliedMigration{SkippedSteps: "0,2"}.Skipped())
+}
+
+func TestSuiteRollbackGroups(t *testing.T) {
+	assert := assert.New(t)
+
+	first := NewGroup(OptGroupID("0001"), OptGroupSQL("CREATE TABLE foo (id int)"), OptGroupDownSQL("DROP TABLE foo"))
+	second := NewGroup(OptGroupID("0002"), OptGroupSQL("CREATE TABLE bar (id int)"), OptGroupDownSQL("DROP TABLE bar"))
+	third := NewVersion("0003", "CREATE TABLE baz (id int)")
+	s := New(OptGroups(first, second, third))
+
+	applied := []AppliedMigration{
+		{ID: "0001", Checksum: first.Checksum()},
+		{ID: "0002", Checksum: second.Checksum()},
+		{ID: "0000", Checksum: "missing"},
+	}
+	groups, err := s.rollbackGroups(applied, "", RollbackOptions{})
+	assert.Nil(err)
+	assert.Len(groups, 2)
+	assert.Equal("0002", groups[0].ID)
+	assert.Equal("0001", groups[1].ID)
+
+	groups, err = s.rollbackGroups(applied, "0001", RollbackOptions{})
+	assert.Nil(err)
+	assert.Len(groups, 1)
+	assert.Equal("0002", groups[0].ID)
+
+	_, err = s.rollbackGroups(applied, "not-a-version", RollbackOptions{})
+	assert.True(ex.Is(err, ErrMigrationNotFound))
+
+	applied = append(applied, AppliedMigration{ID: "0003", Checksum: third.Checksum()})
+	_, err = s.rollbackGroups(applied, "0001", RollbackOptions{})
+	assert.True(IsMigrationIrreversible(err))
+
+	groups, err = s.rollbackGroups(applied, "0001", RollbackOptions{Force: true})
+	assert.Nil(err)
+	assert.Len(groups, 2)
+	assert.Equal("0003", groups[0].ID)
+}
+
+func TestSuiteRollback(t *testing.T) {
+	assert := assert.New(t)
+	testSchemaName := buildTestSchemaName()
+	assert.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("CREATE SCHEMA %s", testSchemaName))))
+	defer func() {
+		assert.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE;", testSchemaName))))
+	}()
+
+	trackingTable := testSchemaName + ".schema_migrations"
+	suite := func() *Suite {
+		return New(OptLog(logger.None()), OptTrackingTable(trackingTable), OptGroups(
+			NewGroup(OptGroupID("0001"), OptGroupSQL(fmt.Sprintf("CREATE TABLE %s.foo (id int)", testSchemaName)), OptGroupDownSQL(fmt.Sprintf("DROP TABLE %s.foo", testSchemaName))),
+			NewGroup(OptGroupID("0002"), OptGroupSQL(fmt.Sprintf("CREATE TABLE %s.bar (id int)", testSchemaName)), OptGroupDownSQL(fmt.Sprintf("DROP TABLE %s.bar", testSchemaName))),
+			NewVersion("0003", fmt.Sprintf("CREATE TABLE %s.baz (id int)", testSchemaName)),
+		))
+	}
+	assert.Nil(suite().Apply(context.Background(), defaultDB()))
+
+	assert.True(IsMigrationIrreversible(suite().Rollback(context.Background(), defaultDB(), "0001")))
+
+	s := suite()
+	assert.Nil(s.Rollback(context.Background(), defaultDB(), "0001", OptRollbackForce()))
+	applied, skipped, _, total := s.Results()
+	assert.Equal(0, applied)
+	assert.Equal(1, skipped)
+	assert.Equal(1, s.RolledBack)
+	assert.Equal(2, total)
+
+	exists, err := PredicateTableExistsInSchema(defaultDB(), nil, testSchemaName, "bar")
+	assert.Nil(err)
+	assert.False(exists)
+	exists, err = PredicateTableExistsInSchema(defaultDB(), nil, testSchemaName, "baz")
+	assert.Nil(err)
+	assert.True(exists, "forced rollbacks without down actions leave the schema")
+
+	statuses, err := suite().Status(context.Background(), defaultDB())
+	assert.Nil(err)
+	assert.Equal(StatusApplied, statuses[0].Status)
+	assert.Equal(StatusPending, statuses[1].Status)
+	assert.Equal(StatusPending, statuses[2].Status)
+}
+
+func TestSuiteRollbackSkippedSteps(t *testing.T) {
+	assert := assert.New(t)
+	testSchemaName := buildTestSchemaName()
+	assert.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("CREATE SCHEMA %s", testSchemaName))))
+	defer func() {
+		assert.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE;", testSchemaName))))
+	}()
+	assert.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("CREATE TABLE %s.users (id int)", testSchemaName))))
+
+	s := New(OptLog(logger.None()), OptTrackingTable(testSchemaName+".schema_migrations"), OptGroups(
+		NewGroup(OptGroupID("0001"), OptGroupActions(NewStepWithDown(
+			TableNotExistsInSchema(testSchemaName, "users"),
+			Statements(fmt.Sprintf("CREATE TABLE %s.users (id int)", testSchemaName)),
+			Statements(fmt.Sprintf("DROP TABLE %s.users", testSchemaName)),
+		))),
+	))
+	assert.Nil(s.Apply(context.Background(), defaultDB()))
+	assert.Nil(s.Rollback(context.Background(), defaultDB(), ""))
+
+	exists, err := PredicateTableExistsInSchema(defaultDB(), nil, testSchemaName, "users")
+	assert.Nil(err)
+	assert.True(exists, "rolling back should not drop a table the migration didn't create")
+
+	statuses, err := s.Status(context.Background(), defaultDB())
+	assert.Nil(err)
+	assert.Equal(StatusPending, statuses[0].Status)
+}
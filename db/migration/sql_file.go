@@ -0,0 +1,309 @@
+package migration
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// ReadSQLDir reads versioned migrations from the `NNNN_name.up.sql` and `NNNN_name.down.sql` files of a directory.
+// See `ReadSQLFiles`.
+func ReadSQLDir(path string, options ...GroupOption) ([]*Group, error) {
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, ex.New(err)
+	}
+	files := map[string][]byte{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		contents, err := ioutil.ReadFile(filepath.Join(path, entry.Name()))
+		if err != nil {
+			return nil, ex.New(err)
+		}
+		files[entry.Name()] = contents
+	}
+	return ReadSQLFiles(files, options...)
+}
+
+// ReadSQLBindata reads versioned migrations from the sql files of a bundle generated with `bindata`,
+// i.e. its `BinaryAssets` map of paths to assets with a `Contents() ([]byte, error)` method.
+// See `ReadSQLFiles`.
+func ReadSQLBindata(assets interface{}, options ...GroupOption) ([]*Group, error) {
+	value := reflect.ValueOf(assets)
+	if value.Kind() != reflect.Map || value.Type().Key().Kind() != reflect.String {
+		return nil, ex.New(ErrSQLFileInvalid, ex.OptMessagef("bindata assets must be a map of paths to assets; %T", assets))
+	}
+	files := map[string][]byte{}
+	iter := value.MapRange()
+	for iter.Next() {
+		path := iter.Key().String()
+		if filepath.Ext(path) != ".sql" {
+			continue
+		}
+		asset, ok := iter.Value().Interface().(bindataAsset)
+		if !ok {
+			return nil, ex.New(ErrSQLFileInvalid, ex.OptMessagef("path: %s; bindata asset has no contents", path))
+		}
+		contents, err := asset.Contents()
+		if err != nil {
+			return nil, ex.New(err, ex.OptMessagef("path: %s", path))
+		}
+		files[path] = contents
+	}
+	return ReadSQLFiles(files, options...)
+}
+
+// ReadSQLFiles returns versioned migrations, ordered by their numeric prefix, from sql files by name.
+//
+// Files are named `NNNN_name.up.sql`, and optionally `NNNN_name.down.sql` to roll them back; the migration's
+// ID is `NNNN_name`. Each file is executed as a single statement. Leading comments can be headers:
+//
+//	-- guard: table_not_exists users
+//	-- transaction: false
+//
+// A guard runs the file only if it passes, i.e. `TableNotExists("users")`, though the migration is recorded as
+// applied either way; guards are the snake case names of the guard functions, with their arguments separated
+// by spaces, and `if_exists` and `if_not_exists` take the rest of the line as their statement. A transaction
+// header of false applies `OptGroupSkipTransaction`, and is only read from up files.
+//
+// The down file of a guarded up file isn't run on rollback if the guard skipped the up file. Other files and
+// comments are ignored, and the options are applied to each group.
+func ReadSQLFiles(files map[string][]byte, options ...GroupOption) ([]*Group, error) {
+	ups := map[string]sqlFile{}
+	downs := map[string]sqlFile{}
+	for name, contents := range files {
+		if filepath.Ext(name) != ".sql" {
+			continue
+		}
+		file, err := parseSQLFile(name, contents)
+		if err != nil {
+			return nil, err
+		}
+		byID := ups
+		if file.Down {
+			byID = downs
+		}
+		if _, ok := byID[file.ID]; ok {
+			return nil, ex.New(ErrMigrationDuplicate, ex.OptMessagef("id: %s", file.ID))
+		}
+		byID[file.ID] = file
+	}
+
+	var sorted []sqlFile
+	for _, up := range ups {
+		sorted = append(sorted, up)
+	}
+	for id := range downs {
+		if _, ok := ups[id]; !ok {
+			return nil, ex.New(ErrSQLFileInvalid, ex.OptMessagef("id: %s; down file has no up file", id))
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Prefix != sorted[j].Prefix {
+			return sorted[i].Prefix < sorted[j].Prefix
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	groups := make([]*Group, 0, len(sorted))
+	for index, up := range sorted {
+		if index > 0 && sorted[index-1].Prefix == up.Prefix {
+			return nil, ex.New(ErrMigrationDuplicate, ex.OptMessagef("prefix: %d; %s, %s", up.Prefix, sorted[index-1].ID, up.ID))
+		}
+		var down *sqlFile
+		if file, ok := downs[up.ID]; ok {
+			down = &file
+		}
+		groupOptions := append([]GroupOption{OptGroupID(up.ID), up.groupOption(down)}, options...)
+		if up.SkipTransaction {
+			groupOptions = append(groupOptions, OptGroupSkipTransaction())
+		}
+		groups = append(groups, NewGroup(groupOptions...))
+	}
+	return groups, nil
+}
+
+// --------------------------------------------------------------------------------
+// helpers
+// --------------------------------------------------------------------------------
+
+var sqlFileName = regexp.MustCompile(`^(([0-9]+)_[^.]+)\.(up|down)\.sql$`)
+
+// bindataAsset is the interface of a `bindata` generated `BinaryFile`.
+type bindataAsset interface {
+	Contents() ([]byte, error)
+}
+
+// sqlFile is a parsed migration sql file.
+type sqlFile struct {
+	ID              string
+	Prefix          uint64
+	Down            bool
+	SQL             string
+	Guard           GuardFunc
+	SkipTransaction bool
+}
+
+// groupOption returns an option that adds the file's sql to a group, with the down file's sql to undo it, if set.
+// If the file has a guard, the down file's sql is the down action of the guarded step, so it isn't run on
+// rollback if the guard skipped the file.
+func (sf sqlFile) groupOption(down *sqlFile) GroupOption {
+	if sf.Guard == nil {
+		return func(g *Group) {
+			OptGroupSQL(sf.SQL)(g)
+			if down != nil {
+				OptGroupDown(down.action())(g)
+			}
+		}
+	}
+	return func(g *Group) {
+		g.SQL = append(g.SQL, sf.SQL)
+		step := NewStep(sf.Guard, sqlAction{sf.SQL}.Action)
+		if down != nil {
+			step.Down = down.action().Action
+		}
+		g.Actions = append(g.Actions, step)
+	}
+}
+
+// action returns the file's sql as an actionable, guarded if it has a guard.
+func (sf sqlFile) action() Actionable {
+	if sf.Guard == nil {
+		return sqlAction{sf.SQL}
+	}
+	return NewStep(sf.Guard, sqlAction{sf.SQL}.Action)
+}
+
+// parseSQLFile parses a migration sql file's name and headers.
+func parseSQLFile(path string, contents []byte) (file sqlFile, err error) {
+	matches := sqlFileName.FindStringSubmatch(filepath.Base(path))
+	if matches == nil {
+		err = ex.New(ErrSQLFileInvalid, ex.OptMessagef("path: %s; name must be `NNNN_name.up.sql` or `NNNN_name.down.sql`", path))
+		return
+	}
+	file.ID = matches[1]
+	if file.Prefix, err = strconv.ParseUint(matches[2], 10, 64); err != nil {
+		err = ex.New(ErrSQLFileInvalid, ex.OptMessagef("path: %s", path), ex.OptInner(err))
+		return
+	}
+	file.Down = matches[3] == "down"
+	file.SQL = string(contents)
+
+	for _, line := range strings.Split(file.SQL, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "--") {
+			break
+		}
+		pieces := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(line, "--")), ":", 2)
+		if len(pieces) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(pieces[1])
+		switch strings.ToLower(strings.TrimSpace(pieces[0])) {
+		case "guard":
+			if file.Guard, err = parseSQLFileGuard(path, value); err != nil {
+				return
+			}
+		case "transaction":
+			var transaction bool
+			if transaction, err = strconv.ParseBool(value); err != nil {
+				err = ex.New(ErrSQLFileInvalid, ex.OptMessagef("path: %s; transaction must be true or false", path))
+				return
+			}
+			file.SkipTransaction = !transaction
+		}
+	}
+	return
+}
+
+// parseSQLFileGuard parses a guard header.
+func parseSQLFileGuard(path, value string) (GuardFunc, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return nil, ex.New(ErrSQLFileInvalid, ex.OptMessagef("path: %s; guard is empty", path))
+	}
+	name, args := strings.ToLower(fields[0]), fields[1:]
+	switch name {
+	case "always":
+		if len(args) == 0 {
+			return Always(), nil
+		}
+	case "if_exists", "if_not_exists":
+		statement := strings.TrimSpace(strings.TrimPrefix(value, fields[0]))
+		if statement == "" {
+			break
+		}
+		if name == "if_exists" {
+			return IfExists(statement), nil
+		}
+		return IfNotExists(statement), nil
+	default:
+		if guards, ok := sqlFileGuards[name]; ok {
+			if guard, ok := guards[len(args)]; ok {
+				return guard(args...), nil
+			}
+		} else {
+			return nil, ex.New(ErrSQLFileInvalid, ex.OptMessagef("path: %s; unknown guard: %s", path, name))
+		}
+	}
+	return nil, ex.New(ErrSQLFileInvalid, ex.OptMessagef("path: %s; wrong number of guard arguments: %s", path, value))
+}
+
+// sqlFileGuards are the guards of sql file headers by name and number of arguments.
+var sqlFileGuards = map[string]map[int]func(...string) GuardFunc{
+	"table_exists": {
+		1: func(args ...string) GuardFunc { return TableExists(args[0]) },
+		2: func(args ...string) GuardFunc { return TableExistsInSchema(args[0], args[1]) },
+	},
+	"table_not_exists": {
+		1: func(args ...string) GuardFunc { return TableNotExists(args[0]) },
+		2: func(args ...string) GuardFunc { return TableNotExistsInSchema(args[0], args[1]) },
+	},
+	"column_exists": {
+		2: func(args ...string) GuardFunc { return ColumnExists(args[0], args[1]) },
+		3: func(args ...string) GuardFunc { return ColumnExistsInSchema(args[0], args[1], args[2]) },
+	},
+	"column_not_exists": {
+		2: func(args ...string) GuardFunc { return ColumnNotExists(args[0], args[1]) },
+		3: func(args ...string) GuardFunc { return ColumnNotExistsInSchema(args[0], args[1], args[2]) },
+	},
+	"constraint_exists": {
+		2: func(args ...string) GuardFunc { return ConstraintExists(args[0], args[1]) },
+		3: func(args ...string) GuardFunc { return ConstraintExistsInSchema(args[0], args[1], args[2]) },
+	},
+	"constraint_not_exists": {
+		2: func(args ...string) GuardFunc { return ConstraintNotExists(args[0], args[1]) },
+		3: func(args ...string) GuardFunc { return ConstraintNotExistsInSchema(args[0], args[1], args[2]) },
+	},
+	"index_exists": {
+		2: func(args ...string) GuardFunc { return IndexExists(args[0], args[1]) },
+		3: func(args ...string) GuardFunc { return IndexExistsInSchema(args[0], args[1], args[2]) },
+	},
+	"index_not_exists": {
+		2: func(args ...string) GuardFunc { return IndexNotExists(args[0], args[1]) },
+		3: func(args ...string) GuardFunc { return IndexNotExistsInSchema(args[0], args[1], args[2]) },
+	},
+	"role_exists": {
+		1: func(args ...string) GuardFunc { return RoleExists(args[0]) },
+	},
+	"role_not_exists": {
+		1: func(args ...string) GuardFunc { return RoleNotExists(args[0]) },
+	},
+	"schema_exists": {
+		1: func(args ...string) GuardFunc { return SchemaExists(args[0]) },
+	},
+	"schema_not_exists": {
+		1: func(args ...string) GuardFunc { return SchemaNotExists(args[0]) },
+	},
+}
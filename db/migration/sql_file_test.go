@@ -0,0 +1,126 @@
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestReadSQLDir(t *testing.T) {
+	assert := assert.New(t)
+
+	groups, err := ReadSQLDir("testdata/sql")
+	assert.Nil(err)
+	assert.Len(groups, 3)
+
+	assert.Equal("0001_create_users", groups[0].ID)
+	assert.Len(groups[0].SQL, 1)
+	assert.Len(groups[0].Actions, 1)
+	step, isStep := groups[0].Actions[0].(*Step)
+	assert.True(isStep, "guarded files are steps")
+	assert.NotNil(step.Down, "the down file of a guarded file is the step's down action")
+	assert.Empty(groups[0].Down)
+	assert.Len(groups[0].DownActions(), 1)
+	assert.Empty(groups[0].downActions(map[int]bool{0: true}), "the down file should not run if the guard skipped the up file")
+	assert.False(groups[0].SkipTransaction)
+
+	assert.Equal("0002_index_users_email", groups[1].ID)
+	assert.True(groups[1].SkipTransaction)
+	assert.Nil(groups[1].DownActions())
+
+	assert.Equal("0010_create_teams", groups[2].ID)
+	assert.NotEmpty(groups[2].Checksum())
+
+	plan := new(Plan)
+	assert.Nil(groups[2].Actions[0].Action(WithPlan(context.Background(), plan), nil, nil))
+	plan.step(StatApplied, "")
+	assert.Len(plan.Steps[0].Statements, 1)
+	assert.Contains(plan.Steps[0].Statements[0].Statement, "CREATE TABLE teams")
+	assert.Contains(plan.Steps[0].Statements[0].Statement, "ALTER TABLE users")
+}
+
+func TestReadSQLFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	groups, err := ReadSQLFiles(map[string][]byte{
+		"migrations/10_second.up.sql": []byte("SELECT 2"),
+		"migrations/9_first.up.sql":   []byte("SELECT 1"),
+		"migrations/README.md":        []byte("not a migration"),
+	}, OptGroupSkipTransaction())
+	assert.Nil(err)
+	assert.Len(groups, 2)
+	assert.Equal("9_first", groups[0].ID)
+	assert.Equal("10_second", groups[1].ID)
+	assert.True(groups[0].SkipTransaction)
+
+	_, err = ReadSQLFiles(map[string][]byte{"first.up.sql": []byte("SELECT 1")})
+	assert.True(IsSQLFileInvalid(err))
+	_, err = ReadSQLFiles(map[string][]byte{"0001_first.down.sql": []byte("SELECT 1")})
+	assert.True(IsSQLFileInvalid(err))
+	_, err = ReadSQLFiles(map[string][]byte{"0001_first.up.sql": []byte("SELECT 1"), "0001_second.up.sql": []byte("SELECT 2")})
+	assert.True(ex.Is(err, ErrMigrationDuplicate))
+	_, err = ReadSQLFiles(map[string][]byte{"a/0001_first.up.sql": []byte("SELECT 1"), "b/0001_first.up.sql": []byte("SELECT 2")})
+	assert.True(ex.Is(err, ErrMigrationDuplicate))
+	_, err = ReadSQLFiles(map[string][]byte{"0001_first.up.sql": []byte("-- transaction: sometimes\nSELECT 1")})
+	assert.True(IsSQLFileInvalid(err))
+}
+
+func TestParseSQLFileGuard(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, value := range []string{
+		"always",
+		"table_exists users",
+		"table_not_exists public users",
+		"column_exists users email",
+		"index_not_exists public users ix_users_email",
+		"schema_not_exists reporting",
+		"if_not_exists SELECT 1 FROM users WHERE email = 'admin'",
+	} {
+		guard, err := parseSQLFileGuard("0001_test.up.sql", value)
+		assert.Nil(err, value)
+		assert.NotNil(guard, value)
+	}
+	for _, value := range []string{
+		"",
+		"table_exists",
+		"table_exists public users extra",
+		"if_exists",
+		"always sometimes",
+		"not_a_guard users",
+	} {
+		_, err := parseSQLFileGuard("0001_test.up.sql", value)
+		assert.True(IsSQLFileInvalid(err), value)
+	}
+
+	// headers are only read from leading comments.
+	file, err := parseSQLFile("0001_test.up.sql", []byte("-- a comment\n\n-- guard: table_not_exists users\nCREATE TABLE users (id int);\n-- transaction: false\n"))
+	assert.Nil(err)
+	assert.NotNil(file.Guard)
+	assert.False(file.SkipTransaction)
+}
+
+type testBindataAsset []byte
+
+func (tba testBindataAsset) Contents() ([]byte, error) { return tba, nil }
+
+func TestReadSQLBindata(t *testing.T) {
+	assert := assert.New(t)
+
+	groups, err := ReadSQLBindata(map[string]*testBindataAsset{
+		"sql/0001_first.up.sql":   {'S', 'E', 'L', 'E', 'C', 'T', ' ', '1'},
+		"sql/0001_first.down.sql": {'S', 'E', 'L', 'E', 'C', 'T', ' ', '0'},
+		"index.html":              nil,
+	})
+	assert.Nil(err)
+	assert.Len(groups, 1)
+	assert.Equal([]string{"SELECT 1"}, groups[0].SQL)
+	assert.Len(groups[0].DownActions(), 1)
+
+	_, err = ReadSQLBindata([]string{"0001_first.up.sql"})
+	assert.True(IsSQLFileInvalid(err))
+	_, err = ReadSQLBindata(map[string]string{"0001_first.up.sql": "SELECT 1"})
+	assert.True(IsSQLFileInvalid(err))
+}
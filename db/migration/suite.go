@@ -34,14 +34,20 @@ func NewWithActions(actions ...Actionable) *Suite {
 type Suite struct {
 	Log    logger.Log
 	Groups []*Group
-
-	Applied int
-	Skipped int
-	Failed  int
-	Total   int
+	// TrackingTable is the table versioned migrations are recorded in, which defaults to `DefaultTrackingTable`.
+	TrackingTable string
+
+	Applied    int
+	Skipped    int
+	Failed     int
+	RolledBack int
+	Total      int
 }
 
 // Apply applies the suite.
+//
+// If the suite has versioned migrations, it holds an advisory lock while it applies them, and verifies
+// the applied migrations before it applies any groups.
 func (s *Suite) Apply(ctx context.Context, c *db.Connection) (err error) {
 	defer s.WriteStats(ctx)
 	defer func() {
@@ -50,16 +56,51 @@ func (s *Suite) Apply(ctx context.Context, c *db.Connection) (err error) {
 		}
 	}()
 
-	for _, group := range s.Groups {
-		if err = group.Action(WithSuite(ctx, s), c); err != nil {
+	var applied map[string]bool
+	if s.IsVersioned() {
+		var lock *db.AdvisoryLock
+		if lock, err = db.Lock(ctx, c, s.TrackingTableOrDefault()); err != nil {
+			return
+		}
+		defer func() { err = ex.Nest(err, lock.Unlock(ctx)) }()
+
+		if err = s.createTrackingTable(ctx, c); err != nil {
 			return
 		}
+		var migrations []AppliedMigration
+		if migrations, err = s.applied(ctx, c, nil); err != nil {
+			return
+		}
+		if applied, err = s.verify(migrations); err != nil {
+			return
+		}
+	}
+
+	for _, group := range s.Groups {
+		if group.ID == "" {
+			if err = group.Action(WithSuite(ctx, s), c); err != nil {
+				return
+			}
+			continue
+		}
+		groupCtx := WithLabel(WithSuite(ctx, s), group.ID)
+		if applied[group.ID] {
+			s.Skipf(groupCtx, "already applied")
+			continue
+		}
+		if err = group.run(withStepsRun(groupCtx), c, group.Actions, s.recordApplied(group)); err != nil {
+			return s.Error(groupCtx, err)
+		}
+		s.Applyf(groupCtx, "applied")
 	}
 	return
 }
 
 // Applyf writes an applied step message.
 func (s *Suite) Applyf(ctx context.Context, format string, args ...interface{}) {
+	if s.planned(ctx, PlanWouldApply, fmt.Sprintf(format, args...)) {
+		return
+	}
 	s.Applied = s.Applied + 1
 	s.Total = s.Total + 1
 	s.Write(ctx, StatApplied, fmt.Sprintf(format, args...))
@@ -67,6 +108,9 @@ func (s *Suite) Applyf(ctx context.Context, format string, args ...interface{})
 
 // Skipf skips a given step.
 func (s *Suite) Skipf(ctx context.Context, format string, args ...interface{}) {
+	if s.planned(ctx, PlanWouldSkip, fmt.Sprintf(format, args...)) {
+		return
+	}
 	s.Skipped = s.Skipped + 1
 	s.Total = s.Total + 1
 	s.Write(ctx, StatSkipped, fmt.Sprintf(format, args...))
@@ -74,6 +118,9 @@ func (s *Suite) Skipf(ctx context.Context, format string, args ...interface{}) {
 
 // Errorf writes an error for a given step.
 func (s *Suite) Errorf(ctx context.Context, format string, args ...interface{}) {
+	if s.planned(ctx, StatFailed, fmt.Sprintf(format, args...)) {
+		return
+	}
 	s.Failed = s.Failed + 1
 	s.Total = s.Total + 1
 	s.Write(ctx, StatFailed, fmt.Sprintf(format, args...))
@@ -81,6 +128,9 @@ func (s *Suite) Errorf(ctx context.Context, format string, args ...interface{})
 
 // Error
 func (s *Suite) Error(ctx context.Context, err error) error {
+	if s.planned(ctx, StatFailed, fmt.Sprintf("%v", err)) {
+		return err
+	}
 	s.Failed = s.Failed + 1
 	s.Total = s.Total + 1
 	s.Write(ctx, StatFailed, fmt.Sprintf("%v", err))
@@ -93,10 +143,21 @@ func (s *Suite) Write(ctx context.Context, result, body string) {
 
 // WriteStats writes the stats if a logger is configured.
 func (s *Suite) WriteStats(ctx context.Context) {
-	logger.MaybeTrigger(ctx, s.Log, NewStatsEvent(s.Applied, s.Skipped, s.Failed, s.Total))
+	logger.MaybeTrigger(ctx, s.Log, NewStatsEvent(s.Applied, s.Skipped, s.Failed, s.Total).WithRolledBack(s.RolledBack))
 }
 
 // Results provides a window into the results of this migration
 func (s *Suite) Results() (applied, skipped, failed, total int) {
 	return s.Applied, s.Skipped, s.Failed, s.Total
 }
+
+// planned adds a step to the context's plan, if it has one, and returns if it did.
+// Planned steps aren't counted or written.
+func (s *Suite) planned(ctx context.Context, result, body string) bool {
+	plan := GetContextPlan(ctx)
+	if plan == nil {
+		return false
+	}
+	plan.step(result, body, GetContextLabels(ctx)...)
+	return true
+}
@@ -20,6 +20,13 @@ func OptGroups(groups ...*Group) SuiteOption {
 	}
 }
 
+// OptTrackingTable sets the table versioned migrations are recorded in.
+func OptTrackingTable(table string) SuiteOption {
+	return func(s *Suite) {
+		s.TrackingTable = table
+	}
+}
+
 // OptLog allows you to add a logger to the Suite.
 func OptLog(log logger.Log) SuiteOption {
 	return func(s *Suite) {
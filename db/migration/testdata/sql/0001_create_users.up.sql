@@ -0,0 +1,2 @@
+-- guard: table_not_exists users
+CREATE TABLE users (id serial primary key, email text not null);
@@ -0,0 +1,3 @@
+-- indexes are created concurrently, which can't be done in a transaction.
+-- transaction: false
+CREATE INDEX CONCURRENTLY ix_users_email ON users (email);
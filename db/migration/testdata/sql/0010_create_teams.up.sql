@@ -0,0 +1,2 @@
+CREATE TABLE teams (id serial primary key, name text not null);
+ALTER TABLE users ADD COLUMN team_id int references teams(id);
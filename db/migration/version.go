@@ -0,0 +1,242 @@
+package migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/ex"
+)
+
+// NewVersion returns a versioned migration that executes sql statements.
+//
+// Versioned migrations are applied in the order they are in a suite, once; they are recorded in the
+// suite's tracking table, with the checksum of their sql, in the same transaction they are applied in.
+// Groups with an ID built from steps, e.g. with `OptGroupID` and `OptGroupActions`, are versioned migrations too.
+// Applying a suite fails if an applied migration's sql has changed, or if a pending migration precedes
+// an applied migration. Groups without an ID are applied as before, and rely on their guards.
+func NewVersion(id string, statements ...string) *Group {
+	return NewGroup(OptGroupID(id), OptGroupSQL(statements...))
+}
+
+// Checksum returns the hex encoded sha256 checksum of the sql a group's `Statements` and `Exec` actions execute,
+// including those of guarded steps, or an empty string if it has none.
+//
+// The sql is captured by running the group's actions with a plan, as `Suite.Plan` does, but without a connection
+// or evaluating guards. Other actions aren't part of the checksum, so versioned migrations are only checked for
+// changes to their sql.
+func (ga *Group) Checksum() string {
+	statements := ga.statements()
+	if len(statements) == 0 {
+		return ""
+	}
+	hash := sha256.New()
+	for _, statement := range statements {
+		hash.Write([]byte(strings.TrimSpace(statement.Statement)))
+		if len(statement.Args) > 0 {
+			hash.Write([]byte(fmt.Sprintf("%v", statement.Args)))
+		}
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Status is the status of a versioned migration.
+type Status struct {
+	ID         string
+	Status     string
+	Checksum   string
+	AppliedUTC time.Time
+}
+
+// AppliedMigration is a row of the tracking table.
+type AppliedMigration struct {
+	ID         string    `db:"id,pk"`
+	Checksum   string    `db:"checksum"`
+	AppliedUTC time.Time `db:"applied_utc"`
+	// SkippedSteps are the comma separated indexes of the steps whose guards skipped them.
+	SkippedSteps string `db:"skipped_steps"`
+}
+
+// Skipped returns the indexes of the steps whose guards skipped them when the migration was applied.
+func (am AppliedMigration) Skipped() map[int]bool {
+	skipped := map[int]bool{}
+	for _, value := range strings.Split(am.SkippedSteps, ",") {
+		if index, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			skipped[index] = true
+		}
+	}
+	return skipped
+}
+
+// Status returns the status of the suite's versioned migrations, in order, followed by
+// applied migrations that aren't in the suite.
+func (s *Suite) Status(ctx context.Context, c *db.Connection) ([]Status, error) {
+	applied, err := s.applied(ctx, c, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.statuses(applied)
+}
+
+// IsVersioned returns if any of the suite's groups are versioned migrations.
+func (s *Suite) IsVersioned() bool {
+	for _, group := range s.Groups {
+		if group.ID != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// TrackingTableOrDefault returns the tracking table or a default.
+func (s *Suite) TrackingTableOrDefault() string {
+	if s.TrackingTable != "" {
+		return s.TrackingTable
+	}
+	return DefaultTrackingTable
+}
+
+// --------------------------------------------------------------------------------
+// helpers
+// --------------------------------------------------------------------------------
+
+// statements returns the statements captured by running the group's actions, and the bodies of its steps,
+// with a plan.
+func (ga *Group) statements() []PlanStatement {
+	plan := new(Plan)
+	ctx := WithPlan(context.Background(), plan)
+	for _, action := range ga.Actions {
+		body := Action(action.Action)
+		if step, ok := action.(*Step); ok {
+			body = step.Body
+		}
+		captureAction(ctx, body)
+	}
+	return plan.statements
+}
+
+// captureAction runs an action with a plan context and no connection, ignoring its result.
+// Actions that use the connection fail or panic, and capture nothing more.
+func captureAction(ctx context.Context, action Action) {
+	if action == nil {
+		return
+	}
+	defer func() { _ = recover() }()
+	_ = action(ctx, nil, nil)
+}
+
+// sqlAction is an actionable that executes statements serially.
+type sqlAction []string
+
+func (sa sqlAction) Action(ctx context.Context, c *db.Connection, tx *sql.Tx) error {
+	return Statements(sa...)(ctx, c, tx)
+}
+
+// createTrackingTable creates the tracking table if it doesn't exist.
+func (s *Suite) createTrackingTable(ctx context.Context, c *db.Connection) error {
+	return db.IgnoreExecResult(c.Invoke(db.OptContext(ctx)).Exec(
+		"CREATE TABLE IF NOT EXISTS " + s.TrackingTableOrDefault() + " (id text primary key, checksum text not null, applied_utc timestamp not null, skipped_steps text not null default '')",
+	))
+}
+
+// applied returns the applied migrations, ordered by when they were applied, or none if the tracking table doesn't exist.
+// It reads from the primary, as replicas may lag behind migrations that were just applied.
+func (s *Suite) applied(ctx context.Context, c *db.Connection, tx *sql.Tx) (applied []AppliedMigration, err error) {
+	var exists bool
+	if pieces := strings.SplitN(s.TrackingTableOrDefault(), ".", 2); len(pieces) == 2 {
+		exists, err = PredicateTableExistsInSchema(c, tx, pieces[0], pieces[1])
+	} else {
+		exists, err = PredicateTableExists(c, tx, pieces[0])
+	}
+	if err != nil || !exists {
+		return
+	}
+	err = c.Invoke(db.OptContext(ctx), db.OptTx(tx), db.OptPrimary()).Query("SELECT id, checksum, applied_utc, skipped_steps FROM " + s.TrackingTableOrDefault() + " ORDER BY applied_utc, id").OutMany(&applied)
+	return
+}
+
+// recordApplied returns an action that records a migration as applied, with the steps that were
+// skipped, so their down actions aren't run when it's rolled back.
+func (s *Suite) recordApplied(group *Group) Action {
+	return func(ctx context.Context, c *db.Connection, tx *sql.Tx) error {
+		var skipped []string
+		for _, index := range group.SkippedSteps(getContextStepsRun(ctx)) {
+			skipped = append(skipped, strconv.Itoa(index))
+		}
+		return db.IgnoreExecResult(c.Invoke(db.OptContext(ctx), db.OptTx(tx)).Exec(
+			"INSERT INTO "+s.TrackingTableOrDefault()+" (id, checksum, applied_utc, skipped_steps) VALUES ($1, $2, $3, $4)",
+			group.ID, group.Checksum(), time.Now().UTC(), strings.Join(skipped, ","),
+		))
+	}
+}
+
+// statuses returns the statuses of the suite's versioned migrations given the applied migrations.
+func (s *Suite) statuses(applied []AppliedMigration) ([]Status, error) {
+	appliedByID := make(map[string]AppliedMigration, len(applied))
+	for _, migration := range applied {
+		appliedByID[migration.ID] = migration
+	}
+
+	var statuses []Status
+	ids := map[string]bool{}
+	lastApplied := -1
+	for _, group := range s.Groups {
+		if group.ID == "" {
+			continue
+		}
+		if ids[group.ID] {
+			return nil, ex.New(ErrMigrationDuplicate, ex.OptMessagef("id: %s", group.ID))
+		}
+		ids[group.ID] = true
+
+		status := Status{ID: group.ID, Status: StatusPending, Checksum: group.Checksum()}
+		if migration, ok := appliedByID[group.ID]; ok {
+			status.Status = StatusApplied
+			status.AppliedUTC = migration.AppliedUTC
+			if status.Checksum != "" && status.Checksum != migration.Checksum {
+				status.Status = StatusModified
+			}
+			lastApplied = len(statuses)
+		}
+		statuses = append(statuses, status)
+	}
+	for index := 0; index < lastApplied; index++ {
+		if statuses[index].Status == StatusPending {
+			statuses[index].Status = StatusOutOfOrder
+		}
+	}
+	for _, migration := range applied {
+		if !ids[migration.ID] {
+			statuses = append(statuses, Status{ID: migration.ID, Status: StatusMissing, Checksum: migration.Checksum, AppliedUTC: migration.AppliedUTC})
+		}
+	}
+	return statuses, nil
+}
+
+// verify returns the ids of the applied migrations, and an error if any were modified, or if any
+// pending migrations precede them.
+func (s *Suite) verify(applied []AppliedMigration) (map[string]bool, error) {
+	statuses, err := s.statuses(applied)
+	if err != nil {
+		return nil, err
+	}
+	ids := map[string]bool{}
+	for _, status := range statuses {
+		switch status.Status {
+		case StatusModified:
+			return nil, ex.New(ErrMigrationModified, ex.OptMessagef("id: %s", status.ID))
+		case StatusOutOfOrder:
+			return nil, ex.New(ErrMigrationOutOfOrder, ex.OptMessagef("id: %s", status.ID))
+		case StatusApplied:
+			ids[status.ID] = true
+		}
+	}
+	return ids, nil
+}
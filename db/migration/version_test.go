@@ -0,0 +1,180 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+)
+
+func TestGroupChecksum(t *testing.T) {
+	assert := assert.New(t)
+
+	group := NewVersion("0001", "CREATE TABLE foo (id int)", "CREATE INDEX ix_foo_id ON foo (id)")
+	assert.Equal("0001", group.ID)
+	assert.Len(group.SQL, 2)
+	assert.Len(group.Actions, 1)
+	assert.Len(group.Checksum(), 64)
+
+	assert.Equal(group.Checksum(), NewVersion("0001", "CREATE TABLE foo (id int)\n", "  CREATE INDEX ix_foo_id ON foo (id)").Checksum())
+	assert.NotEqual(group.Checksum(), NewVersion("0001", "CREATE TABLE foo (id bigint)", "CREATE INDEX ix_foo_id ON foo (id)").Checksum())
+	assert.NotEqual(group.Checksum(), NewVersion("0001", "CREATE TABLE foo (id int)CREATE INDEX ix_foo_id ON foo (id)").Checksum())
+	assert.Empty(NewGroup(OptGroupID("0002")).Checksum())
+}
+
+func TestGroupChecksumSteps(t *testing.T) {
+	assert := assert.New(t)
+
+	newGroup := func(statement string, args ...interface{}) *Group {
+		return NewGroup(OptGroupID("0001"), OptGroupActions(
+			NewStep(TableNotExists("foo"), Statements("CREATE TABLE foo (id int)")),
+			NewStep(Always(), Exec(statement, args...)),
+		))
+	}
+	group := newGroup("INSERT INTO foo (id) VALUES ($1)", 1)
+	assert.Len(group.Checksum(), 64)
+	assert.Equal(group.Checksum(), newGroup("INSERT INTO foo (id) VALUES ($1)", 1).Checksum())
+	assert.NotEqual(group.Checksum(), newGroup("INSERT INTO foo (id) VALUES ($1)", 2).Checksum())
+	assert.Equal(NewVersion("0001", "CREATE TABLE foo (id int)").Checksum(), NewGroup(OptGroupID("0001"), OptGroupActions(NewStep(TableNotExists("foo"), Statements("CREATE TABLE foo (id int)")))).Checksum())
+
+	var ran bool
+	custom := NewGroup(OptGroupID("0002"), OptGroupActions(NewStep(Always(), func(ctx context.Context, c *db.Connection, tx *sql.Tx) error {
+		ran = true
+		return db.IgnoreExecResult(c.Invoke(db.OptTx(tx)).Exec("CREATE TABLE bar (id int)"))
+	})))
+	assert.Empty(custom.Checksum(), "actions that aren't captured shouldn't be part of the checksum")
+	assert.True(ran)
+
+	edited := NewGroup(OptGroupID("0001"), OptGroupActions(NewStep(TableNotExists("foo"), Statements("CREATE TABLE foo (id bigint)"))))
+	applied := NewGroup(OptGroupID("0001"), OptGroupActions(NewStep(TableNotExists("foo"), Statements("CREATE TABLE foo (id int)"))))
+	_, err := New(OptGroups(edited)).verify([]AppliedMigration{{ID: "0001", Checksum: applied.Checksum()}})
+	assert.True(IsMigrationModified(err))
+}
+
+func TestSuiteStatuses(t *testing.T) {
+	assert := assert.New(t)
+
+	first := NewVersion("0001", "CREATE TABLE foo (id int)")
+	second := NewVersion("0002", "CREATE TABLE bar (id int)")
+	third := NewGroup(OptGroupID("0003"), OptGroupActions(NewStep(Always(), NoOp)))
+	s := New(OptGroups(first, NewGroupWithActions(NewStep(Always(), NoOp)), second, third))
+	assert.True(s.IsVersioned())
+	assert.False(New(OptGroups(NewGroupWithActions(NewStep(Always(), NoOp)))).IsVersioned())
+
+	now := time.Now().UTC()
+	statuses, err := s.statuses([]AppliedMigration{
+		{ID: "0001", Checksum: first.Checksum(), AppliedUTC: now},
+		{ID: "0000", Checksum: "old", AppliedUTC: now},
+	})
+	assert.Nil(err)
+	assert.Len(statuses, 4)
+	assert.Equal(Status{ID: "0001", Status: StatusApplied, Checksum: first.Checksum(), AppliedUTC: now}, statuses[0])
+	assert.Equal(StatusPending, statuses[1].Status)
+	assert.Equal("0003", statuses[2].ID)
+	assert.Equal(StatusPending, statuses[2].Status)
+	assert.Equal(Status{ID: "0000", Status: StatusMissing, Checksum: "old", AppliedUTC: now}, statuses[3])
+
+	applied, err := s.verify([]AppliedMigration{{ID: "0001", Checksum: first.Checksum()}})
+	assert.Nil(err)
+	assert.Equal(map[string]bool{"0001": true}, applied)
+
+	// migrations without sql aren't checked for changes.
+	_, err = s.verify([]AppliedMigration{{ID: "0001", Checksum: first.Checksum()}, {ID: "0002", Checksum: second.Checksum()}, {ID: "0003", Checksum: "any"}})
+	assert.Nil(err)
+
+	statuses, err = s.statuses([]AppliedMigration{{ID: "0001", Checksum: "modified"}})
+	assert.Nil(err)
+	assert.Equal(StatusModified, statuses[0].Status)
+	_, err = s.verify([]AppliedMigration{{ID: "0001", Checksum: "modified"}})
+	assert.True(IsMigrationModified(err))
+
+	statuses, err = s.statuses([]AppliedMigration{{ID: "0002", Checksum: second.Checksum()}})
+	assert.Nil(err)
+	assert.Equal(StatusOutOfOrder, statuses[0].Status)
+	assert.Equal(StatusApplied, statuses[1].Status)
+	_, err = s.verify([]AppliedMigration{{ID: "0002", Checksum: second.Checksum()}})
+	assert.True(IsMigrationOutOfOrder(err))
+
+	_, err = New(OptGroups(first, first)).statuses(nil)
+	assert.True(ex.Is(err, ErrMigrationDuplicate))
+}
+
+func TestSuiteTrackingTableOrDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(DefaultTrackingTable, New().TrackingTableOrDefault())
+	assert.Equal("test_migrations", New(OptTrackingTable("test_migrations")).TrackingTableOrDefault())
+}
+
+func TestSuiteAppliedReadsPrimary(t *testing.T) {
+	assert := assert.New(t)
+
+	open := func(port int) *sql.DB {
+		conn, err := sql.Open("postgres", fmt.Sprintf("host=127.0.0.1 port=%d sslmode=disable connect_timeout=1", port))
+		assert.Nil(err)
+		return conn
+	}
+	c := &db.Connection{Connection: open(1), Replicas: []*db.Replica{db.NewReplica(open(2))}}
+	defer c.Close()
+
+	// neither address is listening, so which one was dialed is in the error.
+	_, err := New(OptTrackingTable("test.schema_migrations")).applied(context.Background(), c, nil)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "127.0.0.1:1:")
+}
+
+func TestSuiteApplyVersioned(t *testing.T) {
+	assert := assert.New(t)
+	testSchemaName := buildTestSchemaName()
+	assert.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("CREATE SCHEMA %s", testSchemaName))))
+	defer func() {
+		assert.Nil(db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE;", testSchemaName))))
+	}()
+
+	trackingTable := testSchemaName + ".schema_migrations"
+	versions := []*Group{
+		NewVersion("0001", fmt.Sprintf("CREATE TABLE %s.foo (id int)", testSchemaName)),
+		NewVersion("0002", fmt.Sprintf("ALTER TABLE %s.foo ADD COLUMN name text", testSchemaName)),
+	}
+	s := New(OptLog(logger.None()), OptTrackingTable(trackingTable), OptGroups(versions...))
+	assert.Nil(s.Apply(context.Background(), defaultDB()))
+	applied, skipped, _, _ := s.Results()
+	assert.Equal(2, applied)
+	assert.Equal(0, skipped)
+
+	// applying again skips the applied migrations.
+	s = New(OptLog(logger.None()), OptTrackingTable(trackingTable), OptGroups(versions...))
+	assert.Nil(s.Apply(context.Background(), defaultDB()))
+	applied, skipped, _, _ = s.Results()
+	assert.Equal(0, applied)
+	assert.Equal(2, skipped)
+
+	var count int
+	_, err := defaultDB().Query(fmt.Sprintf("SELECT count(*) FROM %s", trackingTable)).Scan(&count)
+	assert.Nil(err)
+	assert.Equal(2, count)
+
+	modified := New(OptLog(logger.None()), OptTrackingTable(trackingTable), OptGroups(
+		versions[0],
+		NewVersion("0002", fmt.Sprintf("ALTER TABLE %s.foo ADD COLUMN email text", testSchemaName)),
+	))
+	assert.True(IsMigrationModified(modified.Apply(context.Background(), defaultDB())))
+
+	outOfOrder := New(OptLog(logger.None()), OptTrackingTable(trackingTable), OptGroups(
+		versions[0],
+		NewVersion("0001a", fmt.Sprintf("CREATE TABLE %s.bar (id int)", testSchemaName)),
+		versions[1],
+	))
+	assert.True(IsMigrationOutOfOrder(outOfOrder.Apply(context.Background(), defaultDB())))
+
+	statuses, err := outOfOrder.Status(context.Background(), defaultDB())
+	assert.Nil(err)
+	assert.Len(statuses, 3)
+	assert.Equal(StatusOutOfOrder, statuses[1].Status)
+}
@@ -0,0 +1,249 @@
+package db
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// QueryNamed returns a new query object for a sql query with named parameters, e.g. `:id`,
+// bound from a map or a struct using `db` tags.
+//
+//	var users []User
+//	err := conn.Invoke().QueryNamed("SELECT * FROM users WHERE status = :status AND id IN (:ids)", map[string]interface{}{
+//		"status": "active",
+//		"ids":    ids,
+//	}).OutMany(&users)
+//
+// See `NamedStatement.Bind` for how parameters are bound.
+func (i *Invocation) QueryNamed(statement string, arg interface{}) *Query {
+	query, args, err := i.bindNamed(statement, arg)
+	if err != nil {
+		return &Query{Invocation: i, Err: err}
+	}
+	return i.Query(query, args...)
+}
+
+// ExecNamed executes a sql statement with named parameters, e.g. `:id`,
+// bound from a map or a struct using `db` tags.
+//
+// See `NamedStatement.Bind` for how parameters are bound.
+func (i *Invocation) ExecNamed(statement string, arg interface{}) (sql.Result, error) {
+	query, args, err := i.bindNamed(statement, arg)
+	if err != nil {
+		return nil, err
+	}
+	return i.Exec(query, args...)
+}
+
+func (i *Invocation) bindNamed(statement string, arg interface{}) (string, []interface{}, error) {
+	var named *NamedStatement
+	if i.PlanCache != nil {
+		named = i.PlanCache.Named(statement)
+	} else {
+		named = ParseNamed(statement)
+	}
+	return named.Bind(arg)
+}
+
+// ParseNamed parses a statement with named parameters, e.g. `:id`.
+//
+// Names start with a letter or underscore; casts (`::`), and colons in string literals,
+// quoted identifiers and comments are left as is.
+func ParseNamed(statement string) *NamedStatement {
+	named := &NamedStatement{Statement: statement}
+	seen := map[string]bool{}
+
+	var text strings.Builder
+	for index := 0; index < len(statement); index++ {
+		c := statement[index]
+		switch {
+		case c == '\'' || c == '"':
+			// a doubled quote, i.e. an escaped quote, is read as two adjacent literals.
+			end := index + 1 + strings.IndexByte(statement[index+1:], c)
+			if end <= index {
+				end = len(statement) - 1
+			}
+			text.WriteString(statement[index : end+1])
+			index = end
+		case c == '-' && strings.HasPrefix(statement[index:], "--"):
+			end := strings.IndexByte(statement[index:], '\n')
+			if end < 0 {
+				end = len(statement) - index
+			}
+			text.WriteString(statement[index : index+end])
+			index += end - 1
+		case c == '/' && strings.HasPrefix(statement[index:], "/*"):
+			end := len(statement)
+			if closing := strings.Index(statement[index+2:], "*/"); closing >= 0 {
+				end = index + 2 + closing + 2
+			}
+			text.WriteString(statement[index:end])
+			index = end - 1
+		case c == ':' && strings.HasPrefix(statement[index:], "::"):
+			text.WriteString("::")
+			index++
+		case c == ':' && index+1 < len(statement) && isNameStart(statement[index+1]):
+			end := index + 1
+			for end < len(statement) && isNamePart(statement[end]) {
+				end++
+			}
+			name := statement[index+1 : end]
+			named.parts = append(named.parts, namedPart{Text: text.String(), Name: name})
+			text.Reset()
+			if !seen[name] {
+				seen[name] = true
+				named.Names = append(named.Names, name)
+			}
+			index = end - 1
+		default:
+			text.WriteByte(c)
+		}
+	}
+	named.parts = append(named.parts, namedPart{Text: text.String()})
+	return named
+}
+
+// NamedStatement is a statement with named parameters.
+type NamedStatement struct {
+	// Statement is the statement as given.
+	Statement string
+	// Names are the distinct parameter names, in the order they are first used.
+	Names []string
+
+	parts []namedPart
+}
+
+// namedPart is statement text followed by a parameter, if it has a name.
+type namedPart struct {
+	Text string
+	Name string
+}
+
+// Bind rewrites the statement with positional parameters, returning the statement and its arguments.
+//
+// The argument can be a map with string keys, or a struct (or a reference to one) whose parameters
+// are the column names from its `db` tags. A name used more than once is bound once. Slices are expanded
+// to one parameter per element, e.g. for `IN (:ids)`, unless they are `[]byte` or a `driver.Valuer`.
+//
+// Names missing from the argument return `ErrNamedParameterMissing`. Map keys that aren't used by the
+// statement return `ErrNamedParameterUnused`; struct columns that aren't used are ignored.
+func (ns *NamedStatement) Bind(arg interface{}) (statement string, args []interface{}, err error) {
+	var values map[string]interface{}
+	var isMap bool
+	if values, isMap, err = namedValues(arg); err != nil {
+		return
+	}
+	if isMap {
+		used := map[string]bool{}
+		for _, name := range ns.Names {
+			used[name] = true
+		}
+		for key := range values {
+			if !used[key] {
+				err = Error(ErrNamedParameterUnused, ex.OptMessagef("name: %s", key))
+				return
+			}
+		}
+	}
+
+	placeholders := map[string]string{}
+	for _, name := range ns.Names {
+		value, ok := values[name]
+		if !ok {
+			err = Error(ErrNamedParameterMissing, ex.OptMessagef("name: %s", name))
+			return
+		}
+		elements, isSlice := namedSliceElements(value)
+		if !isSlice {
+			args = append(args, value)
+			placeholders[name] = "$" + strconv.Itoa(len(args))
+			continue
+		}
+		if len(elements) == 0 {
+			err = Error(ErrNamedParameterEmpty, ex.OptMessagef("name: %s", name))
+			return
+		}
+		positions := make([]string, len(elements))
+		for index, element := range elements {
+			args = append(args, element)
+			positions[index] = "$" + strconv.Itoa(len(args))
+		}
+		placeholders[name] = strings.Join(positions, ",")
+	}
+
+	var output strings.Builder
+	for _, part := range ns.parts {
+		output.WriteString(part.Text)
+		if part.Name != "" {
+			output.WriteString(placeholders[part.Name])
+		}
+	}
+	statement = output.String()
+	return
+}
+
+// namedValues returns the parameter values of a map or struct argument.
+func namedValues(arg interface{}) (values map[string]interface{}, isMap bool, err error) {
+	if arg == nil {
+		return
+	}
+	if typed, ok := arg.(map[string]interface{}); ok {
+		return typed, true, nil
+	}
+	argValue := ReflectValue(arg)
+	switch argValue.Kind() {
+	case reflect.Map:
+		if argValue.Type().Key().Kind() != reflect.String {
+			err = Error(ErrNamedParameterInvalid, ex.OptMessagef("type: %T", arg))
+			return
+		}
+		values = make(map[string]interface{}, argValue.Len())
+		for _, key := range argValue.MapKeys() {
+			values[key.String()] = argValue.MapIndex(key).Interface()
+		}
+		isMap = true
+	case reflect.Struct:
+		cols := CachedColumnCollectionFromInstance(arg)
+		values = make(map[string]interface{}, cols.Len())
+		columnValues := cols.ColumnValues(arg)
+		for index, name := range cols.ColumnNames() {
+			values[name] = columnValues[index]
+		}
+	default:
+		err = Error(ErrNamedParameterInvalid, ex.OptMessagef("type: %T", arg))
+	}
+	return
+}
+
+// namedSliceElements returns the elements of a value that is expanded to multiple parameters.
+func namedSliceElements(value interface{}) (elements []interface{}, isSlice bool) {
+	if _, isValuer := value.(driver.Valuer); isValuer {
+		return
+	}
+	if _, isBytes := value.([]byte); isBytes {
+		return
+	}
+	sliceValue := reflect.ValueOf(value)
+	if sliceValue.Kind() != reflect.Slice && sliceValue.Kind() != reflect.Array {
+		return
+	}
+	isSlice = true
+	elements = make([]interface{}, sliceValue.Len())
+	for index := range elements {
+		elements[index] = sliceValue.Index(index).Interface()
+	}
+	return
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNamePart(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
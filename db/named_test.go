@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/lib/pq"
+)
+
+func TestParseNamed(t *testing.T) {
+	assert := assert.New(t)
+
+	named := ParseNamed("SELECT * FROM users WHERE id = :id AND status = :status OR parent_id = :id")
+	assert.Equal([]string{"id", "status"}, named.Names)
+
+	named = ParseNamed(`SELECT created::date, ':not_a_name', "col:umn", arr[1:2] FROM t -- :comment
+	WHERE /* :block */ id = :user_id1`)
+	assert.Equal([]string{"user_id1"}, named.Names)
+
+	named = ParseNamed("SELECT 'it''s :escaped' WHERE id = :id")
+	assert.Equal([]string{"id"}, named.Names)
+
+	named = ParseNamed("SELECT 'unterminated :name")
+	assert.Empty(named.Names)
+	statement, args, err := named.Bind(nil)
+	assert.Nil(err)
+	assert.Equal("SELECT 'unterminated :name", statement)
+	assert.Empty(args)
+}
+
+func TestNamedStatementBindMap(t *testing.T) {
+	assert := assert.New(t)
+
+	named := ParseNamed("SELECT * FROM users WHERE id = :id AND status IN (:statuses) OR parent_id = :id")
+	statement, args, err := named.Bind(map[string]interface{}{
+		"id":       "foo",
+		"statuses": []string{"active", "pending"},
+	})
+	assert.Nil(err)
+	assert.Equal("SELECT * FROM users WHERE id = $1 AND status IN ($2,$3) OR parent_id = $1", statement)
+	assert.Equal([]interface{}{"foo", "active", "pending"}, args)
+
+	statement, args, err = named.Bind(map[string]string{"id": "foo", "statuses": "active"})
+	assert.Nil(err)
+	assert.Equal("SELECT * FROM users WHERE id = $1 AND status IN ($2) OR parent_id = $1", statement)
+	assert.Equal([]interface{}{"foo", "active"}, args)
+
+	_, _, err = named.Bind(map[string]interface{}{"id": "foo"})
+	assert.True(IsNamedParameterMissing(err))
+
+	_, _, err = named.Bind(map[string]interface{}{"id": "foo", "statuses": []string{"active"}, "extra": true})
+	assert.True(IsNamedParameterUnused(err))
+
+	_, _, err = named.Bind(map[string]interface{}{"id": "foo", "statuses": []string{}})
+	assert.True(ex.Is(err, ErrNamedParameterEmpty))
+
+	_, _, err = named.Bind(map[int]interface{}{1: "foo"})
+	assert.True(ex.Is(err, ErrNamedParameterInvalid))
+
+	_, _, err = named.Bind("foo")
+	assert.True(ex.Is(err, ErrNamedParameterInvalid))
+}
+
+type namedTest struct {
+	ID    string         `db:"id,pk"`
+	Tags  pq.StringArray `db:"tags"`
+	Bytes []byte         `db:"bytes"`
+	Meta  []string       `db:"meta,json"`
+	Other string         `db:"other"`
+}
+
+func TestNamedStatementBindStruct(t *testing.T) {
+	assert := assert.New(t)
+
+	obj := namedTest{ID: "foo", Tags: pq.StringArray{"a", "b"}, Bytes: []byte("bytes"), Meta: []string{"c"}}
+	statement, args, err := ParseNamed("SELECT :id, :tags, :bytes, :meta").Bind(&obj)
+	assert.Nil(err)
+	assert.Equal("SELECT $1, $2, $3, $4", statement)
+	assert.Equal([]interface{}{"foo", pq.StringArray{"a", "b"}, []byte("bytes"), `["c"]`}, args)
+
+	_, _, err = ParseNamed("SELECT :missing").Bind(obj)
+	assert.True(IsNamedParameterMissing(err))
+}
+
+func TestPlanCacheNamed(t *testing.T) {
+	assert := assert.New(t)
+
+	pc := NewPlanCache(nil)
+	named := pc.Named("SELECT :id")
+	assert.Equal([]string{"id"}, named.Names)
+	assert.True(named == pc.Named("SELECT :id"), "should return the cached statement")
+
+	for x := 0; x < DefaultPlanCacheNamedLimit; x++ {
+		pc.Named(fmt.Sprintf("SELECT :id, %d", x))
+	}
+	assert.Equal(DefaultPlanCacheNamedLimit, int(pc.namedCount))
+	_, cached := pc.NamedCache.Load(fmt.Sprintf("SELECT :id, %d", DefaultPlanCacheNamedLimit-1))
+	assert.False(cached, "statements past the limit should not be cached")
+	assert.Equal([]string{"id", "other"}, pc.Named("SELECT :id, :other").Names, "statements past the limit should still be parsed")
+
+	assert.Nil(pc.Close())
+	assert.Zero(pc.namedCount)
+	_, cached = pc.NamedCache.Load("SELECT :id")
+	assert.False(cached, "closing the cache should clear the named statements")
+}
+
+func TestInvocationQueryNamedError(t *testing.T) {
+	assert := assert.New(t)
+
+	db := new(recordingDB)
+	i := &Invocation{DB: db, Context: context.Background()}
+	_, err := i.QueryNamed("SELECT :id", nil).Any()
+	assert.True(IsNamedParameterMissing(err))
+	_, err = i.ExecNamed("SELECT :id", map[string]interface{}{})
+	assert.True(IsNamedParameterMissing(err))
+	assert.Empty(db.Statements)
+
+	_, err = i.ExecNamed("SELECT :id", map[string]interface{}{"id": 1})
+	assert.NotNil(err)
+	assert.Equal([]string{"SELECT $1"}, db.Statements)
+}
+
+func TestConnectionQueryNamed(t *testing.T) {
+	assert := assert.New(t)
+
+	var value int
+	_, err := defaultDB().Invoke().QueryNamed("SELECT count(*) FROM generate_series(1, 10) as id WHERE id IN (:ids) AND id > :min", map[string]interface{}{
+		"ids": []int{1, 2, 3, 4},
+		"min": 1,
+	}).Scan(&value)
+	assert.Nil(err)
+	assert.Equal(3, value)
+}
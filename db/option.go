@@ -41,6 +41,39 @@ func OptStatementInterceptor(interceptor StatementInterceptor) Option {
 	}
 }
 
+// OptAuditSink sets the audit sink invocations of the connection write audit records to.
+func OptAuditSink(sink AuditSink) Option {
+	return func(c *Connection) error {
+		c.AuditSink = sink
+		return nil
+	}
+}
+
+// OptEncrypter sets the encrypter for encrypted columns, tagged `db:"...,encrypted"`, on the connection.
+func OptEncrypter(encrypter ColumnEncrypter) Option {
+	return func(c *Connection) error {
+		c.Encrypter = encrypter
+		return nil
+	}
+}
+
+// OptBlindIndexKey sets the key blind indexes of encrypted columns are computed with on the connection.
+func OptBlindIndexKey(key []byte) Option {
+	return func(c *Connection) error {
+		c.BlindIndexKey = key
+		return nil
+	}
+}
+
+// OptPlanCache sets the plan cache on the connection, which caches the parsed statements of
+// `QueryNamed` and `ExecNamed`. If the cache has no driver connection, it's set when the connection is opened.
+func OptPlanCache(cache *PlanCache) Option {
+	return func(c *Connection) error {
+		c.PlanCache = cache
+		return nil
+	}
+}
+
 // OptConfig sets the config on a connection.
 func OptConfig(cfg Config) Option {
 	return func(c *Connection) error {
@@ -60,3 +93,23 @@ func OptConfigFromEnv() Option {
 		return nil
 	}
 }
+
+// OptReplica adds a read replica driver connection.
+// The replica selector defaults to round robin if unset.
+func OptReplica(conn *sql.DB) Option {
+	return func(c *Connection) error {
+		c.Replicas = append(c.Replicas, NewReplica(conn))
+		if c.ReplicaSelector == nil {
+			c.ReplicaSelector = ReplicaSelectorRoundRobin()
+		}
+		return nil
+	}
+}
+
+// OptReplicaSelector sets the replica selector on the connection.
+func OptReplicaSelector(selector ReplicaSelector) Option {
+	return func(c *Connection) error {
+		c.ReplicaSelector = selector
+		return nil
+	}
+}
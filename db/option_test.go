@@ -32,4 +32,17 @@ func TestOptions(t *testing.T) {
 	assert.Empty(c.Config.DSN)
 	assert.Nil(OptConfig(Config{DSN: "foo"})(c))
 	assert.Equal("foo", c.Config.DSN)
+
+	assert.Empty(c.Replicas)
+	assert.Nil(c.ReplicaSelector)
+	assert.Nil(OptReplica(&sql.DB{})(c))
+	assert.Len(c.Replicas, 1)
+	assert.NotNil(c.ReplicaSelector)
+
+	assert.Nil(OptReplicaSelector(ReplicaSelectorLeastLoaded())(c))
+	assert.NotNil(c.ReplicaSelector)
+
+	assert.Nil(c.PlanCache)
+	assert.Nil(OptPlanCache(NewPlanCache(nil))(c))
+	assert.NotNil(c.PlanCache)
 }
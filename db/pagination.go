@@ -0,0 +1,310 @@
+package db
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/blend/go-sdk/crypto"
+	"github.com/blend/go-sdk/ex"
+)
+
+// Paginate reads a page of a collection with keyset pagination, returning tokens for the next
+// and previous pages.
+//
+// Rows are ordered by the sort columns, followed by the primary key columns that aren't sort columns
+// as a tiebreaker, and pages start after (or before) the row a token was issued for, rather than at an offset.
+//
+//	var users []User
+//	page, err := conn.Invoke().Paginate(&users, db.Pagination{
+//		Key:   tokenKey,
+//		Sort:  []string{"created_utc"},
+//		Limit: 50,
+//		Token: req.URL.Query().Get("page"),
+//	})
+//
+// The tokens are signed with the pagination key, and `ErrPageTokenInvalid` is returned for tokens that
+// were tampered with or issued for a different sort.
+//
+// Sort columns must not be nullable, i.e. pointers or `sql.Null*` types, as rows with null sort values
+// can't be compared with the row a page starts at; `ErrPaginationSortNullable` is returned for them.
+func (i *Invocation) Paginate(collection interface{}, pagination Pagination) (page Page, err error) {
+	sliceValue := reflect.ValueOf(collection)
+	if sliceValue.Kind() != reflect.Ptr || sliceValue.Elem().Kind() != reflect.Slice {
+		err = Error(ErrCollectionNotSlice)
+		return
+	}
+
+	var token pageToken
+	if pagination.Token != "" {
+		if token, err = pagination.decode(pagination.Token); err != nil {
+			return
+		}
+	} else if len(pagination.Key) == 0 {
+		err = Error(ErrPaginationKeyUnset)
+		return
+	}
+
+	var queryBody string
+	var sortCols *ColumnCollection
+	var args []interface{}
+	if i.Label, queryBody, sortCols, args, err = i.generatePaginate(collection, pagination, token); err != nil {
+		return
+	}
+
+	sliceValue = sliceValue.Elem()
+	from := sliceValue.Len()
+	if err = i.readQuery(queryBody, args...).OutMany(collection); err != nil {
+		return
+	}
+
+	rows := sliceValue.Slice(from, sliceValue.Len())
+	hasMore := rows.Len() > pagination.limit()
+	if hasMore {
+		rows = rows.Slice(0, pagination.limit())
+	}
+	if token.Before {
+		reverseSlice(rows)
+	}
+	sliceValue.Set(sliceValue.Slice(0, from+rows.Len()))
+	if rows.Len() == 0 {
+		return
+	}
+
+	// going forward, there is a previous page if we came from one, and a next page if there are more rows;
+	// going backward it's the other way around.
+	hasNext, hasPrev := hasMore, token.Values != nil
+	if token.Before {
+		hasNext, hasPrev = true, hasMore
+	}
+	if hasNext {
+		if page.Next, err = pagination.encode(pageToken{Sort: pagination.sort(sortCols), Values: pageValues(sortCols, rows.Index(rows.Len()-1))}); err != nil {
+			return
+		}
+	}
+	if hasPrev {
+		if page.Prev, err = pagination.encode(pageToken{Sort: pagination.sort(sortCols), Values: pageValues(sortCols, rows.Index(0)), Before: true}); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Pagination is a request for a page of a collection.
+type Pagination struct {
+	// Key is the key page tokens are signed with.
+	Key []byte
+	// Sort are the columns to order by; primary key columns are added as a tiebreaker.
+	Sort []string
+	// Descending orders by the sort columns descending.
+	Descending bool
+	// Limit is the maximum number of rows in a page, which defaults to `DefaultPaginationLimit`.
+	Limit int
+	// Where is an optional predicate, e.g. `tenant_id = $1`, that rows must also match.
+	Where string
+	// Args are the arguments to the where predicate.
+	Args []interface{}
+	// Token is a page token returned with a previous page, or empty for the first page.
+	Token string
+}
+
+// Page is the tokens for the pages around a page of a collection.
+type Page struct {
+	// Next is the token for the next page, or empty if it's the last page.
+	Next string
+	// Prev is the token for the previous page, or empty if it's the first page.
+	Prev string
+}
+
+// --------------------------------------------------------------------------------
+// helpers
+// --------------------------------------------------------------------------------
+
+// pageToken is the position a page starts at.
+type pageToken struct {
+	// Sort identifies the sort the token was issued for.
+	Sort string `json:"s"`
+	// Values are the sort column values of the row the page starts after, or before.
+	Values []interface{} `json:"v"`
+	// Before is set if the page precedes the row.
+	Before bool `json:"b,omitempty"`
+}
+
+func (p Pagination) limit() int {
+	if p.Limit > 0 {
+		return p.Limit
+	}
+	return DefaultPaginationLimit
+}
+
+// sort returns the sort a token is issued for.
+func (p Pagination) sort(sortCols *ColumnCollection) string {
+	sort := strings.Join(sortCols.ColumnNames(), ",")
+	if p.Descending {
+		return sort + " DESC"
+	}
+	return sort
+}
+
+// encode encodes a token, and signs it with the pagination key.
+func (p Pagination) encode(token pageToken) (string, error) {
+	contents, err := json.Marshal(token)
+	if err != nil {
+		return "", Error(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(contents) + "." + base64.RawURLEncoding.EncodeToString(crypto.HMAC256(p.Key, contents)), nil
+}
+
+// decode decodes a token, checking its signature.
+func (p Pagination) decode(encoded string) (token pageToken, err error) {
+	if len(p.Key) == 0 {
+		err = Error(ErrPaginationKeyUnset)
+		return
+	}
+	pieces := strings.Split(encoded, ".")
+	if len(pieces) != 2 {
+		err = Error(ErrPageTokenInvalid)
+		return
+	}
+	contents, contentsErr := base64.RawURLEncoding.DecodeString(pieces[0])
+	signature, signatureErr := base64.RawURLEncoding.DecodeString(pieces[1])
+	if contentsErr != nil || signatureErr != nil || !hmac.Equal(signature, crypto.HMAC256(p.Key, contents)) {
+		err = Error(ErrPageTokenInvalid)
+		return
+	}
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.UseNumber()
+	if err = decoder.Decode(&token); err != nil {
+		err = Error(ErrPageTokenInvalid, ex.OptInner(err))
+	}
+	return
+}
+
+// pageValues returns the values of the sort columns of a row for a token.
+func pageValues(sortCols *ColumnCollection, row reflect.Value) []interface{} {
+	values := sortCols.ColumnValues(row.Interface())
+	for index, value := range values {
+		if valuer, ok := value.(driver.Valuer); ok {
+			if converted, err := valuer.Value(); err == nil {
+				values[index] = converted
+			}
+		}
+	}
+	return values
+}
+
+// isNullableType returns if a field type can hold a null value, i.e. it's a pointer or interface,
+// or a `sql.Null*` style struct with a `Valid` field.
+func isNullableType(fieldType reflect.Type) bool {
+	switch fieldType.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map:
+		return true
+	case reflect.Struct:
+		valid, ok := fieldType.FieldByName("Valid")
+		return ok && valid.Type.Kind() == reflect.Bool && reflect.PtrTo(fieldType).Implements(reflect.TypeOf((*sql.Scanner)(nil)).Elem())
+	}
+	return false
+}
+
+func reverseSlice(sliceValue reflect.Value) {
+	swap := reflect.Swapper(sliceValue.Interface())
+	for left, right := 0, sliceValue.Len()-1; left < right; left, right = left+1, right-1 {
+		swap(left, right)
+	}
+}
+
+// generatePaginate generates the query for a page, returning the sort columns, including the tiebreaker.
+func (i *Invocation) generatePaginate(collection interface{}, pagination Pagination, token pageToken) (statementLabel, queryBody string, sortCols *ColumnCollection, args []interface{}, err error) {
+	collectionType := ReflectSliceType(collection)
+	tableName := TableNameByType(collectionType)
+
+	cols := CachedColumnCollectionFromType(tableName, collectionType).NotReadOnly()
+	pks := cols.PrimaryKeys()
+	if pks.Len() == 0 {
+		err = Error(ErrNoPrimaryKey)
+		return
+	}
+	lookup := cols.Lookup()
+	sortCols = NewColumnCollection()
+	for _, name := range pagination.Sort {
+		column, ok := lookup[name]
+		if !ok {
+			err = Error(ErrColumnNotFound, ex.OptMessagef("column: %s", name))
+			return
+		}
+		if isNullableType(column.FieldType) {
+			err = Error(ErrPaginationSortNullable, ex.OptMessagef("column: %s", name))
+			return
+		}
+		if !sortCols.HasColumn(name) {
+			sortCols.Add(*column)
+		}
+	}
+	for _, pk := range pks.Columns() {
+		if !sortCols.HasColumn(pk.ColumnName) {
+			sortCols.Add(pk)
+		}
+	}
+
+	if token.Values != nil && (token.Sort != pagination.sort(sortCols) || len(token.Values) != sortCols.Len()) {
+		err = Error(ErrPageTokenInvalid)
+		return
+	}
+
+	// pages before a row are read in reverse order, and reversed after they're read.
+	descending := pagination.Descending != token.Before
+	comparison, direction := " > ", " ASC"
+	if descending {
+		comparison, direction = " < ", " DESC"
+	}
+
+	queryBodyBuffer := i.BufferPool.Get()
+	defer i.BufferPool.Put(queryBodyBuffer)
+
+	queryBodyBuffer.WriteString("SELECT ")
+	queryBodyBuffer.WriteString(cols.ColumnNamesCSV())
+	queryBodyBuffer.WriteString(" FROM ")
+	queryBodyBuffer.WriteString(tableName)
+
+	args = append(args, pagination.Args...)
+	var predicates []string
+	if pagination.Where != "" {
+		predicates = append(predicates, "("+pagination.Where+")")
+	}
+	if softDelete := cols.SoftDeleteColumn(); softDelete != nil && !i.Unscoped {
+		predicates = append(predicates, softDelete.ColumnName+" IS NULL")
+	}
+	if token.Values != nil {
+		params := make([]string, len(token.Values))
+		for index, value := range token.Values {
+			args = append(args, value)
+			params[index] = "$" + strconv.Itoa(len(args))
+		}
+		predicates = append(predicates, "("+strings.Join(sortCols.ColumnNames(), ",")+")"+comparison+"("+strings.Join(params, ",")+")")
+	}
+	if len(predicates) > 0 {
+		queryBodyBuffer.WriteString(" WHERE ")
+		queryBodyBuffer.WriteString(strings.Join(predicates, " AND "))
+	}
+
+	queryBodyBuffer.WriteString(" ORDER BY ")
+	for index, name := range sortCols.ColumnNames() {
+		if index > 0 {
+			queryBodyBuffer.WriteRune(',')
+		}
+		queryBodyBuffer.WriteString(name)
+		queryBodyBuffer.WriteString(direction)
+	}
+	queryBodyBuffer.WriteString(" LIMIT ")
+	queryBodyBuffer.WriteString(strconv.Itoa(pagination.limit() + 1))
+
+	queryBody = queryBodyBuffer.String()
+	statementLabel = i.scopedLabel(tableName+"_paginate", cols.SoftDeleteColumn())
+	return
+}
@@ -0,0 +1,191 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+type paginationTest struct {
+	ID       int    `db:"id,pk"`
+	Category string `db:"category"`
+}
+
+func (paginationTest) TableName() string { return "pagination_test" }
+
+type paginationNullableTest struct {
+	ID        int            `db:"id,pk"`
+	Category  sql.NullString `db:"category"`
+	CreatedBy *string        `db:"created_by"`
+	Rank      int            `db:"rank"`
+}
+
+func (paginationNullableTest) TableName() string { return "pagination_nullable_test" }
+
+type paginationNoPKTest struct {
+	Category string `db:"category"`
+}
+
+func (paginationNoPKTest) TableName() string { return "pagination_no_pk_test" }
+
+func TestPaginationToken(t *testing.T) {
+	assert := assert.New(t)
+
+	pagination := Pagination{Key: []byte("test key")}
+	encoded, err := pagination.encode(pageToken{Sort: "category,id", Values: []interface{}{"foo", 3}, Before: true})
+	assert.Nil(err)
+
+	token, err := pagination.decode(encoded)
+	assert.Nil(err)
+	assert.Equal("category,id", token.Sort)
+	assert.Len(token.Values, 2)
+	assert.Equal("foo", token.Values[0])
+	assert.Equal("3", token.Values[1].(interface{ String() string }).String())
+	assert.True(token.Before)
+
+	_, err = Pagination{Key: []byte("other key")}.decode(encoded)
+	assert.True(IsPageTokenInvalid(err))
+
+	pieces := strings.Split(encoded, ".")
+	tampered, err := Pagination{Key: []byte("other key")}.encode(pageToken{Sort: "category,id", Values: []interface{}{"bar", 3}})
+	assert.Nil(err)
+	_, err = pagination.decode(strings.Split(tampered, ".")[0] + "." + pieces[1])
+	assert.True(IsPageTokenInvalid(err))
+
+	_, err = pagination.decode("not a token")
+	assert.True(IsPageTokenInvalid(err))
+
+	_, err = Pagination{}.decode(encoded)
+	assert.True(ex.Is(err, ErrPaginationKeyUnset))
+}
+
+func TestInvocationGeneratePaginate(t *testing.T) {
+	assert := assert.New(t)
+
+	i := defaultDB().Invoke()
+	pagination := Pagination{Key: []byte("test key"), Sort: []string{"category"}, Limit: 10}
+
+	label, queryBody, sortCols, args, err := i.generatePaginate(&[]paginationTest{}, pagination, pageToken{})
+	assert.Nil(err)
+	assert.Equal("pagination_test_paginate", label)
+	assert.Equal("SELECT id,category FROM pagination_test ORDER BY category ASC,id ASC LIMIT 11", queryBody)
+	assert.Equal([]string{"category", "id"}, sortCols.ColumnNames())
+	assert.Empty(args)
+
+	after := pageToken{Sort: "category,id", Values: []interface{}{"foo", 3}}
+	_, queryBody, _, args, err = i.generatePaginate(&[]paginationTest{}, pagination, after)
+	assert.Nil(err)
+	assert.Equal("SELECT id,category FROM pagination_test WHERE (category,id) > ($1,$2) ORDER BY category ASC,id ASC LIMIT 11", queryBody)
+	assert.Equal([]interface{}{"foo", 3}, args)
+
+	before := pageToken{Sort: "category,id", Values: []interface{}{"foo", 3}, Before: true}
+	_, queryBody, _, _, err = i.generatePaginate(&[]paginationTest{}, pagination, before)
+	assert.Nil(err)
+	assert.Equal("SELECT id,category FROM pagination_test WHERE (category,id) < ($1,$2) ORDER BY category DESC,id DESC LIMIT 11", queryBody)
+
+	descending := pagination
+	descending.Descending = true
+	descending.Where = "category <> $1"
+	descending.Args = []interface{}{"bar"}
+	_, queryBody, _, args, err = i.generatePaginate(&[]paginationTest{}, descending, pageToken{Sort: "category,id DESC", Values: []interface{}{"foo", 3}})
+	assert.Nil(err)
+	assert.Equal("SELECT id,category FROM pagination_test WHERE (category <> $1) AND (category,id) < ($2,$3) ORDER BY category DESC,id DESC LIMIT 11", queryBody)
+	assert.Equal([]interface{}{"bar", "foo", 3}, args)
+
+	// a token issued for a different sort is invalid.
+	_, _, _, _, err = i.generatePaginate(&[]paginationTest{}, descending, after)
+	assert.True(IsPageTokenInvalid(err))
+
+	_, _, _, _, err = i.generatePaginate(&[]paginationTest{}, Pagination{Sort: []string{"not_a_column"}}, pageToken{})
+	assert.True(IsColumnNotFound(err))
+
+	_, _, _, _, err = i.generatePaginate(&[]paginationNoPKTest{}, Pagination{Sort: []string{"category"}}, pageToken{})
+	assert.True(ex.Is(err, ErrNoPrimaryKey))
+}
+
+func TestInvocationGeneratePaginateNullable(t *testing.T) {
+	assert := assert.New(t)
+
+	i := defaultDB().Invoke()
+	for _, column := range []string{"category", "created_by"} {
+		_, _, _, _, err := i.generatePaginate(&[]paginationNullableTest{}, Pagination{Key: []byte("test key"), Sort: []string{column}}, pageToken{})
+		assert.True(ex.Is(err, ErrPaginationSortNullable), column)
+	}
+	_, queryBody, _, _, err := i.generatePaginate(&[]paginationNullableTest{}, Pagination{Key: []byte("test key"), Sort: []string{"rank"}}, pageToken{})
+	assert.Nil(err)
+	assert.Contains(queryBody, "ORDER BY rank ASC,id ASC")
+}
+
+func TestInvocationPaginateErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := defaultDB().Invoke().Paginate([]paginationTest{}, Pagination{Key: []byte("test key")})
+	assert.True(ex.Is(err, ErrCollectionNotSlice))
+
+	_, err = defaultDB().Invoke().Paginate(&[]paginationTest{}, Pagination{})
+	assert.True(ex.Is(err, ErrPaginationKeyUnset))
+
+	_, err = defaultDB().Invoke().Paginate(&[]paginationTest{}, Pagination{Key: []byte("test key"), Token: "not a token"})
+	assert.True(IsPageTokenInvalid(err))
+}
+
+func TestInvocationPaginate(t *testing.T) {
+	assert := assert.New(t)
+	tx, err := defaultDB().Begin()
+	assert.Nil(err)
+	defer tx.Rollback()
+
+	assert.Nil(IgnoreExecResult(defaultDB().Invoke(OptTx(tx)).Exec("CREATE TABLE pagination_test (id int primary key, category text not null)")))
+	var objects []paginationTest
+	for index := 1; index <= 10; index++ {
+		objects = append(objects, paginationTest{ID: index, Category: []string{"a", "b"}[index%2]})
+	}
+	assert.Nil(defaultDB().Invoke(OptTx(tx)).CreateMany(objects))
+
+	// the expected order is by category, then id.
+	expected := []int{2, 4, 6, 8, 10, 1, 3, 5, 7, 9}
+	pagination := Pagination{Key: []byte("test key"), Sort: []string{"category"}, Limit: 4}
+
+	var ids []int
+	var pages []Page
+	for {
+		var page []paginationTest
+		next, err := defaultDB().Invoke(OptTx(tx)).Paginate(&page, pagination)
+		assert.Nil(err)
+		for _, obj := range page {
+			ids = append(ids, obj.ID)
+		}
+		pages = append(pages, next)
+		if next.Next == "" {
+			break
+		}
+		pagination.Token = next.Next
+	}
+	assert.Equal(expected, ids)
+	assert.Len(pages, 3)
+	assert.Empty(pages[0].Prev)
+	assert.NotEmpty(pages[2].Prev)
+
+	// page back from the last page.
+	var previous []paginationTest
+	pagination.Token = pages[2].Prev
+	page, err := defaultDB().Invoke(OptTx(tx)).Paginate(&previous, pagination)
+	assert.Nil(err)
+	assert.Len(previous, 4)
+	assert.Equal(10, previous[0].ID)
+	assert.Equal(5, previous[3].ID)
+	assert.NotEmpty(page.Next)
+	assert.NotEmpty(page.Prev)
+
+	var first []paginationTest
+	pagination.Token = page.Prev
+	page, err = defaultDB().Invoke(OptTx(tx)).Paginate(&first, pagination)
+	assert.Nil(err)
+	assert.Len(first, 4)
+	assert.Equal(2, first[0].ID)
+	assert.Empty(page.Prev)
+	assert.NotEmpty(page.Next)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"sync"
+	"sync/atomic"
 
 	"github.com/blend/go-sdk/ex"
 )
@@ -13,22 +14,34 @@ func NewPlanCache(conn *sql.DB) *PlanCache {
 	return &PlanCache{
 		Connection: conn,
 		Cache:      sync.Map{},
+		NamedCache: sync.Map{},
 	}
 }
 
-// PlanCache is a cache of prepared statements.
+// PlanCache is a cache of prepared statements, and of parsed named statements.
+//
+// Named statements are cached by their text, up to `DefaultPlanCacheNamedLimit` of them;
+// once the limit is reached, further statements are parsed each time they're used.
 type PlanCache struct {
 	Connection *sql.DB
 	Cache      sync.Map
+	NamedCache sync.Map
+
+	namedCount int32
 }
 
 // Close implements io.Closer.
-// It ranges over the cached statements and closes them.
+// It ranges over the cached statements and closes them, and clears the cached named statements.
 func (pc *PlanCache) Close() (err error) {
 	pc.Cache.Range(func(k, v interface{}) bool {
 		err = v.(*sql.Stmt).Close()
 		return err == nil
 	})
+	pc.NamedCache.Range(func(k, _ interface{}) bool {
+		pc.NamedCache.Delete(k)
+		return true
+	})
+	atomic.StoreInt32(&pc.namedCount, 0)
 	return
 }
 
@@ -66,3 +79,19 @@ func (pc *PlanCache) PrepareContext(ctx context.Context, key, statement string)
 	pc.Cache.Store(key, stmt)
 	return stmt, nil
 }
+
+// Named returns a cached parsed named statement, or parses and caches it if the cache isn't full.
+func (pc *PlanCache) Named(statement string) *NamedStatement {
+	if named, ok := pc.NamedCache.Load(statement); ok {
+		return named.(*NamedStatement)
+	}
+	named := ParseNamed(statement)
+	if atomic.AddInt32(&pc.namedCount, 1) > DefaultPlanCacheNamedLimit {
+		atomic.AddInt32(&pc.namedCount, -1)
+		return named
+	}
+	if _, loaded := pc.NamedCache.LoadOrStore(statement, named); loaded {
+		atomic.AddInt32(&pc.namedCount, -1)
+	}
+	return named
+}
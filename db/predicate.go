@@ -0,0 +1,178 @@
+package db
+
+import "reflect"
+
+// Predicate is a condition in a where clause.
+// It writes itself to a statement, adding any arguments it needs.
+type Predicate func(*Statement)
+
+// Eq returns a predicate that a column equals a value.
+// A nil value yields `IS NULL`.
+func Eq(column string, value interface{}) Predicate {
+	if isNil(value) {
+		return IsNull(column)
+	}
+	return compare(column, "=", value)
+}
+
+// NotEq returns a predicate that a column does not equal a value.
+// A nil value yields `IS NOT NULL`.
+func NotEq(column string, value interface{}) Predicate {
+	if isNil(value) {
+		return IsNotNull(column)
+	}
+	return compare(column, "<>", value)
+}
+
+// Lt returns a predicate that a column is less than a value.
+func Lt(column string, value interface{}) Predicate {
+	return compare(column, "<", value)
+}
+
+// Lte returns a predicate that a column is less than or equal to a value.
+func Lte(column string, value interface{}) Predicate {
+	return compare(column, "<=", value)
+}
+
+// Gt returns a predicate that a column is greater than a value.
+func Gt(column string, value interface{}) Predicate {
+	return compare(column, ">", value)
+}
+
+// Gte returns a predicate that a column is greater than or equal to a value.
+func Gte(column string, value interface{}) Predicate {
+	return compare(column, ">=", value)
+}
+
+// Like returns a predicate that a column matches a `LIKE` pattern.
+func Like(column, pattern string) Predicate {
+	return compare(column, "LIKE", pattern)
+}
+
+// ILike returns a predicate that a column matches a case insensitive `ILIKE` pattern.
+func ILike(column, pattern string) Predicate {
+	return compare(column, "ILIKE", pattern)
+}
+
+// In returns a predicate that a column is one of a list of values.
+// A single slice of values is expanded, e.g. `In("id", ids)`. An empty list of values never matches.
+func In(column string, values ...interface{}) Predicate {
+	return list(column, "IN", "FALSE", values)
+}
+
+// NotIn returns a predicate that a column is not one of a list of values.
+// A single slice of values is expanded, e.g. `NotIn("id", ids)`. An empty list of values always matches.
+func NotIn(column string, values ...interface{}) Predicate {
+	return list(column, "NOT IN", "TRUE", values)
+}
+
+// IsNull returns a predicate that a column is null.
+func IsNull(column string) Predicate {
+	return func(s *Statement) {
+		s.WriteColumn(column)
+		s.WriteString(" IS NULL")
+	}
+}
+
+// IsNotNull returns a predicate that a column is not null.
+func IsNotNull(column string) Predicate {
+	return func(s *Statement) {
+		s.WriteColumn(column)
+		s.WriteString(" IS NOT NULL")
+	}
+}
+
+// And returns a predicate that all of the given predicates hold.
+func And(predicates ...Predicate) Predicate {
+	return join(" AND ", "TRUE", predicates)
+}
+
+// Or returns a predicate that any of the given predicates hold.
+func Or(predicates ...Predicate) Predicate {
+	return join(" OR ", "FALSE", predicates)
+}
+
+// Not returns a predicate that negates a given predicate.
+func Not(predicate Predicate) Predicate {
+	return func(s *Statement) {
+		s.WriteString("NOT (")
+		predicate(s)
+		s.WriteString(")")
+	}
+}
+
+func compare(column, operator string, value interface{}) Predicate {
+	return func(s *Statement) {
+		s.WriteColumn(column)
+		s.WriteString(" " + operator + " ")
+		s.WriteArg(value)
+	}
+}
+
+func list(column, operator, empty string, values []interface{}) Predicate {
+	values = expandValues(values)
+	return func(s *Statement) {
+		if len(values) == 0 {
+			s.ValidateColumn(column)
+			s.WriteString(empty)
+			return
+		}
+		s.WriteColumn(column)
+		s.WriteString(" " + operator + " (")
+		for index, value := range values {
+			if index > 0 {
+				s.WriteString(",")
+			}
+			s.WriteArg(value)
+		}
+		s.WriteString(")")
+	}
+}
+
+func join(separator, empty string, predicates []Predicate) Predicate {
+	return func(s *Statement) {
+		switch len(predicates) {
+		case 0:
+			s.WriteString(empty)
+		case 1:
+			predicates[0](s)
+		default:
+			for index, predicate := range predicates {
+				if index > 0 {
+					s.WriteString(separator)
+				}
+				s.WriteString("(")
+				predicate(s)
+				s.WriteString(")")
+			}
+		}
+	}
+}
+
+// expandValues expands a single slice of values, other than a byte slice, into a list of values.
+func expandValues(values []interface{}) []interface{} {
+	if len(values) != 1 || values[0] == nil {
+		return values
+	}
+	rv := reflect.ValueOf(values[0])
+	if (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return values
+	}
+	expanded := make([]interface{}, rv.Len())
+	for index := range expanded {
+		expanded[index] = rv.Index(index).Interface()
+	}
+	return expanded
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
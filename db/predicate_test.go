@@ -0,0 +1,65 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func buildPredicate(predicate Predicate) (string, []interface{}, error) {
+	s := NewStatement("builder_test", Columns(builderTestObj{}))
+	predicate(s)
+	return s.Build()
+}
+
+func TestPredicates(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := [...]struct {
+		Predicate Predicate
+		Expected  string
+		Args      []interface{}
+	}{
+		{Eq("id", 1), "id = $1", []interface{}{1}},
+		{Eq("name", nil), "name IS NULL", nil},
+		{Eq("name", (*string)(nil)), "name IS NULL", nil},
+		{NotEq("id", 1), "id <> $1", []interface{}{1}},
+		{NotEq("name", nil), "name IS NOT NULL", nil},
+		{Lt("id", 1), "id < $1", []interface{}{1}},
+		{Lte("id", 1), "id <= $1", []interface{}{1}},
+		{Gt("id", 1), "id > $1", []interface{}{1}},
+		{Gte("id", 1), "id >= $1", []interface{}{1}},
+		{Like("name", "a%"), "name LIKE $1", []interface{}{"a%"}},
+		{ILike("name", "a%"), "name ILIKE $1", []interface{}{"a%"}},
+		{In("id", 1, 2, 3), "id IN ($1,$2,$3)", []interface{}{1, 2, 3}},
+		{In("id"), "FALSE", nil},
+		{NotIn("id", 1, 2), "id NOT IN ($1,$2)", []interface{}{1, 2}},
+		{NotIn("id"), "TRUE", nil},
+		{In("id", []int{1, 2}), "id IN ($1,$2)", []interface{}{1, 2}},
+		{In("id", []string{}), "FALSE", nil},
+		{NotIn("name", []string{"a"}), "name NOT IN ($1)", []interface{}{"a"}},
+		{In("name", []byte("a")), "name IN ($1)", []interface{}{[]byte("a")}},
+		{IsNull("name"), "name IS NULL", nil},
+		{IsNotNull("name"), "name IS NOT NULL", nil},
+		{And(), "TRUE", nil},
+		{Or(), "FALSE", nil},
+		{And(Eq("id", 1)), "id = $1", []interface{}{1}},
+		{And(Eq("id", 1), Eq("name", "foo")), "(id = $1) AND (name = $2)", []interface{}{1, "foo"}},
+		{Or(Eq("id", 1), And(Gt("id", 5), Lt("id", 10))), "(id = $1) OR ((id > $2) AND (id < $3))", []interface{}{1, 5, 10}},
+		{Not(Eq("id", 1)), "NOT (id = $1)", []interface{}{1}},
+	}
+
+	for _, tc := range testCases {
+		actual, args, err := buildPredicate(tc.Predicate)
+		assert.Nil(err)
+		assert.Equal(tc.Expected, actual)
+		assert.Equal(tc.Args, args, tc.Expected)
+	}
+
+	_, _, err := buildPredicate(Or(Eq("id", 1), Eq("nmae", "foo")))
+	assert.True(IsColumnNotFound(err))
+	_, _, err = buildPredicate(In("nmae"))
+	assert.True(IsColumnNotFound(err), "empty lists should validate their column")
+	_, _, err = buildPredicate(NotIn("nmae", []int{}))
+	assert.True(IsColumnNotFound(err), "empty lists should validate their column")
+}
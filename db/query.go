@@ -3,6 +3,7 @@ package db
 import (
 	"database/sql"
 	"reflect"
+	"strings"
 
 	"github.com/blend/go-sdk/ex"
 )
@@ -16,6 +17,10 @@ type Query struct {
 	Invocation *Invocation
 	Statement  string
 	Args       []interface{}
+	// Err is an error composing the query; if set the query is not run.
+	Err error
+
+	read bool
 }
 
 // Do runs a given query, yielding the raw results.
@@ -78,6 +83,7 @@ func (q *Query) Scan(args ...interface{}) (found bool, err error) {
 // Out writes the query result to a single object via. reflection mapping. If there is more than one result, the first
 // result is mapped to to object, and ErrTooManyRows is returned. Out() will apply column values for any colums
 // in the row result to the object, potentially zeroing existing values out.
+// It calls the object's `AfterGet` hook if it implements it and is found.
 func (q *Query) Out(object interface{}) (found bool, err error) {
 	var rows *sql.Rows
 	defer func() {
@@ -89,11 +95,21 @@ func (q *Query) Out(object interface{}) (found bool, err error) {
 	if err != nil {
 		return
 	}
-	found, err = Out(rows, object)
+	if found, err = Out(rows, object); err != nil || !found {
+		return
+	}
+	if err = q.Invocation.decryptColumns(object); err != nil {
+		return
+	}
+	if err = q.Invocation.preload([]reflect.Value{ReflectValue(object)}); err != nil {
+		return
+	}
+	err = q.Invocation.afterGet(object)
 	return
 }
 
 // OutMany writes the query results to a slice of objects.
+// It calls each object's `AfterGet` hook if it implements it.
 func (q *Query) OutMany(collection interface{}) (err error) {
 	var rows *sql.Rows
 	defer func() {
@@ -105,7 +121,24 @@ func (q *Query) OutMany(collection interface{}) (err error) {
 	if err != nil {
 		return
 	}
-	err = OutMany(rows, collection)
+	existing := sliceLen(collection)
+	if err = OutMany(rows, collection); err != nil {
+		return
+	}
+	if err = eachElement(collection, existing, q.Invocation.decryptColumns); err != nil {
+		return
+	}
+	if len(q.Invocation.Preload) > 0 {
+		var objects []reflect.Value
+		_ = eachElement(collection, existing, func(object interface{}) error {
+			objects = append(objects, ReflectValue(object))
+			return nil
+		})
+		if err = q.Invocation.preload(objects); err != nil {
+			return
+		}
+	}
+	err = eachElement(collection, existing, q.Invocation.afterGet)
 	return
 }
 
@@ -154,8 +187,12 @@ func (q *Query) rowsClose(rows *sql.Rows, err error) error {
 }
 
 func (q *Query) query() (rows *sql.Rows, err error) {
+	if q.Err != nil {
+		err = q.Err
+		return
+	}
 	var queryError error
-	db := q.Invocation.DB
+	db := q.db()
 	ctx := q.Invocation.Context
 	rows, queryError = db.QueryContext(ctx, q.Statement, q.Args...)
 	if queryError != nil && !ex.Is(queryError, sql.ErrNoRows) {
@@ -271,3 +308,35 @@ func Scan(rows *sql.Rows, args ...interface{}) (found bool, err error) {
 	}
 	return
 }
+
+// db returns the db the query runs against; the replica for read only work and selects,
+// otherwise the primary, recording the statement as a write.
+func (q *Query) db() DB {
+	if q.read || q.Invocation.ReadOnly || isSelectStatement(q.Statement) {
+		return q.Invocation.ReadDB()
+	}
+	return q.Invocation.WriteDB()
+}
+
+// selectLocks are the locking clauses that make a select a write.
+var selectLocks = []string{
+	" FOR UPDATE",
+	" FOR NO KEY UPDATE",
+	" FOR SHARE",
+	" FOR KEY SHARE",
+}
+
+// isSelectStatement returns if a statement is a select without a locking clause.
+func isSelectStatement(statement string) bool {
+	statement = strings.TrimSpace(statement)
+	if len(statement) < len("select") || !strings.EqualFold(statement[:len("select")], "select") {
+		return false
+	}
+	statement = strings.ToUpper(strings.Join(strings.Fields(statement), " "))
+	for _, lock := range selectLocks {
+		if strings.Contains(statement, lock) {
+			return false
+		}
+	}
+	return true
+}
@@ -83,6 +83,11 @@ func OptQueryErr(value error) QueryEventOption {
 	return func(e *QueryEvent) { e.Err = value }
 }
 
+// OptQueryRowsAffected sets a field on the query event.
+func OptQueryRowsAffected(value int64) QueryEventOption {
+	return func(e *QueryEvent) { e.RowsAffected = value }
+}
+
 // QueryEvent represents a database query.
 type QueryEvent struct {
 	Database string
@@ -92,6 +97,8 @@ type QueryEvent struct {
 	Body     string
 	Elapsed  time.Duration
 	Err      error
+	// RowsAffected is the number of rows affected by statements that return a result, e.g. `Exec`.
+	RowsAffected int64
 }
 
 // GetFlag implements Event.
@@ -133,12 +140,13 @@ func (e QueryEvent) WriteText(tf logger.TextFormatter, wr io.Writer) {
 // Decompose implements JSONWritable.
 func (e QueryEvent) Decompose() map[string]interface{} {
 	return map[string]interface{}{
-		"engine":   e.Engine,
-		"database": e.Database,
-		"username": e.Username,
-		"label":    e.Label,
-		"body":     e.Body,
-		"err":      e.Err,
-		"elapsed":  timeutil.Milliseconds(e.Elapsed),
+		"engine":       e.Engine,
+		"database":     e.Database,
+		"username":     e.Username,
+		"label":        e.Label,
+		"body":         e.Body,
+		"err":          e.Err,
+		"elapsed":      timeutil.Milliseconds(e.Elapsed),
+		"rowsAffected": e.RowsAffected,
 	}
 }
@@ -23,6 +23,7 @@ func TestQueryEvent(t *testing.T) {
 		OptQueryLabel("event-query-label"),
 		OptQueryElapsed(time.Millisecond),
 		OptQueryErr(fmt.Errorf("test error")),
+		OptQueryRowsAffected(5),
 	)
 
 	assert.Equal("event-body", qe.Body)
@@ -32,6 +33,7 @@ func TestQueryEvent(t *testing.T) {
 	assert.Equal("event-query-label", qe.Label)
 	assert.Equal(time.Millisecond, qe.Elapsed)
 	assert.Equal("test error", qe.Err.Error())
+	assert.Equal(5, qe.RowsAffected)
 
 	buf := new(bytes.Buffer)
 	noColor := logger.TextOutputFormatter{
@@ -58,3 +60,21 @@ func TestQueryEventListener(t *testing.T) {
 	ml(context.Background(), qe)
 	assert.True(didCall)
 }
+
+func TestSlowQueryEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	sqe := NewSlowQueryEvent(NewQueryEvent("select 1", 2*time.Second, OptQueryLabel("label")), time.Second)
+	assert.Equal(SlowQueryFlag, sqe.GetFlag())
+
+	buf := new(bytes.Buffer)
+	sqe.WriteText(logger.TextOutputFormatter{NoColor: true}, buf)
+	assert.Equal("[label] 2s > 1s select 1", buf.String())
+	assert.Equal(1000, sqe.Decompose()["threshold"])
+
+	var didCall bool
+	NewSlowQueryEventListener(func(_ context.Context, e SlowQueryEvent) {
+		didCall = true
+	})(context.Background(), sqe)
+	assert.True(didCall)
+}
@@ -0,0 +1,332 @@
+package db
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// RelationKind is the kind of a relation.
+type RelationKind string
+
+// Relation kinds.
+const (
+	// RelationHasMany is a relation to the rows of another table whose foreign key column references the primary key.
+	// The field is a slice of structs, or of references to structs.
+	RelationHasMany RelationKind = "hasmany"
+	// RelationHasOne is a relation to the row of another table whose foreign key column references the primary key.
+	// The field is a struct, or a reference to a struct.
+	RelationHasOne RelationKind = "hasone"
+	// RelationBelongsTo is a relation to the row of another table that a foreign key column references.
+	// The field is a struct, or a reference to a struct.
+	RelationBelongsTo RelationKind = "belongsto"
+)
+
+// Relation is a relation from a mapped struct to another, declared with a `rel` field tag
+// of its kind and foreign key column, for example:
+//
+//	type Order struct {
+//		ID        int        `db:"id,pk"`
+//		LineItems []LineItem `rel:"hasmany,order_id"`
+//	}
+//
+//	type LineItem struct {
+//		ID      int    `db:"id,pk"`
+//		OrderID int    `db:"order_id"`
+//		Order   *Order `rel:"belongsto,order_id"`
+//	}
+//
+// Fields with a `rel` tag are not columns. Relations are loaded with `OptPreload`.
+type Relation struct {
+	// Kind is the kind of relation.
+	Kind RelationKind
+	// FieldName is the name of the field the related rows are set on.
+	FieldName string
+	// Index is the index of the field.
+	Index int
+	// Type is the related struct type.
+	Type reflect.Type
+	// ForeignKey is the foreign key column; it is on the related table for `hasmany` and `hasone`
+	// relations, and on the table of the struct the relation is declared on for `belongsto` relations.
+	ForeignKey string
+}
+
+// Relations returns the relations of a mapped struct type by field name.
+// The results of this are cached.
+func Relations(t reflect.Type) (map[string]*Relation, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if cached, ok := relationCache.Load(t); ok {
+		typed := cached.(relationCacheEntry)
+		return typed.Relations, typed.Err
+	}
+	relations, err := generateRelationsForType(t)
+	relationCache.Store(t, relationCacheEntry{Relations: relations, Err: err})
+	return relations, err
+}
+
+// --------------------------------------------------------------------------------
+// helpers
+// --------------------------------------------------------------------------------
+
+var relationCache sync.Map
+
+type relationCacheEntry struct {
+	Relations map[string]*Relation
+	Err       error
+}
+
+func generateRelationsForType(t reflect.Type) (map[string]*Relation, error) {
+	relations := map[string]*Relation{}
+	if t.Kind() != reflect.Struct {
+		return relations, nil
+	}
+	for index := 0; index < t.NumField(); index++ {
+		field := t.Field(index)
+		tag, ok := field.Tag.Lookup("rel")
+		if !ok {
+			continue
+		}
+		pieces := strings.Split(tag, ",")
+		if len(pieces) != 2 || pieces[1] == "" {
+			return nil, Error(ErrRelationInvalid, ex.OptMessagef("field: %s, tag: %s", field.Name, tag))
+		}
+		relation := Relation{
+			Kind:       RelationKind(strings.ToLower(strings.TrimSpace(pieces[0]))),
+			FieldName:  field.Name,
+			Index:      index,
+			ForeignKey: strings.TrimSpace(pieces[1]),
+		}
+
+		relatedType := field.Type
+		switch relation.Kind {
+		case RelationHasMany:
+			if relatedType.Kind() != reflect.Slice {
+				return nil, Error(ErrRelationInvalid, ex.OptMessagef("field: %s, hasmany relations must be slices", field.Name))
+			}
+			relatedType = relatedType.Elem()
+		case RelationHasOne, RelationBelongsTo:
+		default:
+			return nil, Error(ErrRelationInvalid, ex.OptMessagef("field: %s, kind: %s", field.Name, relation.Kind))
+		}
+		if relatedType.Kind() == reflect.Ptr {
+			relatedType = relatedType.Elem()
+		}
+		if relatedType.Kind() != reflect.Struct {
+			return nil, Error(ErrRelationInvalid, ex.OptMessagef("field: %s, related type must be a struct", field.Name))
+		}
+		relation.Type = relatedType
+
+		foreignKeyType := relatedType
+		if relation.Kind == RelationBelongsTo {
+			foreignKeyType = t
+		}
+		if !columnsForType(foreignKeyType).HasColumn(relation.ForeignKey) {
+			return nil, Error(ErrColumnNotFound, ex.OptMessagef("field: %s, column: %s", field.Name, relation.ForeignKey))
+		}
+		relations[field.Name] = &relation
+	}
+	return relations, nil
+}
+
+func columnsForType(t reflect.Type) *ColumnCollection {
+	return CachedColumnCollectionFromType(newColumnCacheKey(t), t)
+}
+
+// preload loads the relations named by the invocation's preload paths for a set of struct values.
+func (i *Invocation) preload(parents []reflect.Value) error {
+	if len(i.Preload) == 0 || len(parents) == 0 {
+		return nil
+	}
+	parentType := parents[0].Type()
+	relations, err := Relations(parentType)
+	if err != nil {
+		return err
+	}
+
+	// paths are grouped by their first relation, so each relation is queried once,
+	// and the rest of the paths are preloaded on the related rows.
+	var names []string
+	nested := map[string][]string{}
+	for _, path := range i.Preload {
+		pieces := strings.SplitN(path, ".", 2)
+		if _, ok := nested[pieces[0]]; !ok {
+			names = append(names, pieces[0])
+			nested[pieces[0]] = nil
+		}
+		if len(pieces) > 1 {
+			nested[pieces[0]] = append(nested[pieces[0]], pieces[1])
+		}
+	}
+	for _, name := range names {
+		relation, ok := relations[name]
+		if !ok {
+			return Error(ErrRelationNotFound, ex.OptMessagef("type: %s, relation: %s", parentType.String(), name))
+		}
+		if err = i.preloadRelation(parents, relation, nested[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *Invocation) preloadRelation(parents []reflect.Value, relation *Relation, nested []string) error {
+	parentCols := columnsForType(parents[0].Type())
+	relatedCols := columnsForType(relation.Type)
+
+	// the parent key references, or is referenced by, the related key.
+	var parentKey, relatedKey *Column
+	if relation.Kind == RelationBelongsTo {
+		parentKey = parentCols.Lookup()[relation.ForeignKey]
+		if relatedKey = singlePrimaryKey(relatedCols); relatedKey == nil {
+			return Error(ErrRelationInvalid, ex.OptMessagef("relation: %s, related type must have a single primary key", relation.FieldName))
+		}
+	} else {
+		if parentKey = singlePrimaryKey(parentCols); parentKey == nil {
+			return Error(ErrRelationInvalid, ex.OptMessagef("relation: %s, type must have a single primary key", relation.FieldName))
+		}
+		relatedKey = relatedCols.Lookup()[relation.ForeignKey]
+	}
+
+	var keys []interface{}
+	seen := map[string]bool{}
+	for _, parent := range parents {
+		value := parent.Interface()
+		key, ok := relationKey(parentKey.GetValue(value))
+		if ok && !seen[key] {
+			seen[key] = true
+			keys = append(keys, parentKey.GetValue(value))
+		}
+	}
+
+	related := reflect.ValueOf(makeSliceOfType(relation.Type))
+	if len(keys) > 0 {
+		invocation := i.relatedInvocation(nested)
+		var queryBody string
+		invocation.Label, queryBody = invocation.generatePreload(parents[0].Type(), relation, relatedCols, relatedKey, len(keys))
+		if err := invocation.readQuery(queryBody, keys...).OutMany(related.Interface()); err != nil {
+			return err
+		}
+	}
+
+	byKey := map[string][]reflect.Value{}
+	for index := 0; index < related.Elem().Len(); index++ {
+		row := related.Elem().Index(index)
+		if key, ok := relationKey(relatedKey.GetValue(row.Interface())); ok {
+			byKey[key] = append(byKey[key], row)
+		}
+	}
+	for _, parent := range parents {
+		var rows []reflect.Value
+		if key, ok := relationKey(parentKey.GetValue(parent.Interface())); ok {
+			rows = byKey[key]
+		}
+		setRelation(parent.Field(relation.Index), relation, rows)
+	}
+	return nil
+}
+
+// relatedInvocation returns an invocation for relation queries, that doesn't cancel the context
+// when it finishes, as the parent invocation does that.
+func (i *Invocation) relatedInvocation(preload []string) *Invocation {
+	related := *i
+	related.Cancel = nil
+	related.Preload = preload
+	related.TraceFinisher = nil
+	return &related
+}
+
+func (i *Invocation) generatePreload(parentType reflect.Type, relation *Relation, relatedCols *ColumnCollection, relatedKey *Column, keys int) (statementLabel, queryBody string) {
+	tableName := TableNameByType(relation.Type)
+	cols := relatedCols.NotReadOnly()
+	softDelete := cols.SoftDeleteColumn()
+
+	queryBodyBuffer := i.BufferPool.Get()
+	defer i.BufferPool.Put(queryBodyBuffer)
+
+	queryBodyBuffer.WriteString("SELECT ")
+	queryBodyBuffer.WriteString(cols.ColumnNamesCSV())
+	queryBodyBuffer.WriteString(" FROM ")
+	queryBodyBuffer.WriteString(tableName)
+	queryBodyBuffer.WriteString(" WHERE ")
+	queryBodyBuffer.WriteString(relatedKey.ColumnName)
+	queryBodyBuffer.WriteString(" IN (")
+	queryBodyBuffer.WriteString(ParamTokensCSV(keys))
+	queryBodyBuffer.WriteString(")")
+	i.writeSoftDeleteScope(queryBodyBuffer, " AND ", softDelete)
+	if pk := singlePrimaryKey(relatedCols); pk != nil {
+		queryBodyBuffer.WriteString(" ORDER BY ")
+		queryBodyBuffer.WriteString(pk.ColumnName)
+	}
+
+	queryBody = queryBodyBuffer.String()
+	statementLabel = i.scopedLabel(TableNameByType(parentType)+"_preload_"+strings.ToLower(relation.FieldName), softDelete)
+	return
+}
+
+// setRelation sets a relation field to the related rows.
+// Has many relations are set to an empty slice if there are no related rows.
+func setRelation(field reflect.Value, relation *Relation, rows []reflect.Value) {
+	isPtr := field.Type().Kind() == reflect.Ptr
+	if relation.Kind == RelationHasMany {
+		isPtr = field.Type().Elem().Kind() == reflect.Ptr
+		slice := reflect.MakeSlice(field.Type(), 0, len(rows))
+		for _, row := range rows {
+			if isPtr {
+				slice = reflect.Append(slice, row.Addr())
+			} else {
+				slice = reflect.Append(slice, row)
+			}
+		}
+		field.Set(slice)
+		return
+	}
+	if len(rows) == 0 {
+		field.Set(reflect.Zero(field.Type()))
+		return
+	}
+	if isPtr {
+		field.Set(rows[0].Addr())
+	} else {
+		field.Set(rows[0])
+	}
+}
+
+// singlePrimaryKey returns the primary key column, if there is exactly one.
+func singlePrimaryKey(cols *ColumnCollection) *Column {
+	pks := cols.PrimaryKeys()
+	if pks.Len() != 1 {
+		return nil
+	}
+	return pks.FirstOrDefault()
+}
+
+// relationKey returns a comparable key for a key column value, and false if the value is null.
+func relationKey(value interface{}) (string, bool) {
+	if reflected := reflect.ValueOf(value); reflected.Kind() == reflect.Ptr {
+		if reflected.IsNil() {
+			return "", false
+		}
+		value = reflected.Elem().Interface()
+	}
+	if valuer, ok := value.(driver.Valuer); ok {
+		converted, err := valuer.Value()
+		if err != nil {
+			return "", false
+		}
+		value = converted
+	}
+	if value == nil {
+		return "", false
+	}
+	if typed, ok := value.([]byte); ok {
+		return strconv.Quote(string(typed)), true
+	}
+	return fmt.Sprintf("%v", value), true
+}
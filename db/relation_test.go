@@ -0,0 +1,201 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/uuid"
+)
+
+type relationCustomer struct {
+	ID   int    `db:"id,pk"`
+	Name string `db:"name"`
+}
+
+func (relationCustomer) TableName() string { return "relation_customer" }
+
+type relationOrder struct {
+	ID         int                 `db:"id,pk"`
+	CustomerID *int                `db:"customer_id"`
+	Customer   *relationCustomer   `rel:"belongsto,customer_id"`
+	LineItems  []relationLineItem  `rel:"hasmany,order_id"`
+	Latest     *relationLineItem   `rel:"hasone,order_id"`
+	Refs       []*relationLineItem `rel:"hasmany,order_id"`
+}
+
+func (relationOrder) TableName() string { return "relation_order" }
+
+type relationLineItem struct {
+	ID      int            `db:"id,pk"`
+	OrderID int            `db:"order_id"`
+	SKU     string         `db:"sku"`
+	Order   *relationOrder `rel:"belongsto,order_id"`
+}
+
+func (relationLineItem) TableName() string { return "relation_line_item" }
+
+type relationInvalidTag struct {
+	ID    int                `db:"id,pk"`
+	Items []relationLineItem `rel:"hasmany"`
+}
+
+type relationInvalidKind struct {
+	ID    int                `db:"id,pk"`
+	Items []relationLineItem `rel:"manytomany,order_id"`
+}
+
+type relationInvalidField struct {
+	ID    int              `db:"id,pk"`
+	Items relationLineItem `rel:"hasmany,order_id"`
+}
+
+type relationInvalidColumn struct {
+	ID    int                `db:"id,pk"`
+	Items []relationLineItem `rel:"hasmany,not_a_column"`
+}
+
+func TestRelations(t *testing.T) {
+	assert := assert.New(t)
+
+	relations, err := Relations(reflect.TypeOf(&relationOrder{}))
+	assert.Nil(err)
+	assert.Len(relations, 4)
+
+	customer := relations["Customer"]
+	assert.NotNil(customer)
+	assert.Equal(RelationBelongsTo, customer.Kind)
+	assert.Equal("customer_id", customer.ForeignKey)
+	assert.Equal(reflect.TypeOf(relationCustomer{}), customer.Type)
+
+	lineItems := relations["LineItems"]
+	assert.NotNil(lineItems)
+	assert.Equal(RelationHasMany, lineItems.Kind)
+	assert.Equal("order_id", lineItems.ForeignKey)
+	assert.Equal(reflect.TypeOf(relationLineItem{}), lineItems.Type)
+
+	assert.Equal(RelationHasOne, relations["Latest"].Kind)
+	assert.Equal(reflect.TypeOf(relationLineItem{}), relations["Refs"].Type)
+
+	// relations are not columns.
+	assert.Equal([]string{"id", "customer_id"}, Columns(relationOrder{}).ColumnNames())
+
+	cached, err := Relations(reflect.TypeOf(relationOrder{}))
+	assert.Nil(err)
+	assert.True(cached["Customer"] == customer)
+}
+
+func TestRelationsInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := Relations(reflect.TypeOf(relationInvalidTag{}))
+	assert.True(ex.Is(err, ErrRelationInvalid))
+	_, err = Relations(reflect.TypeOf(relationInvalidKind{}))
+	assert.True(ex.Is(err, ErrRelationInvalid))
+	_, err = Relations(reflect.TypeOf(relationInvalidField{}))
+	assert.True(ex.Is(err, ErrRelationInvalid))
+	_, err = Relations(reflect.TypeOf(relationInvalidColumn{}))
+	assert.True(IsColumnNotFound(err))
+}
+
+func TestRelationKey(t *testing.T) {
+	assert := assert.New(t)
+
+	key, ok := relationKey(1)
+	assert.True(ok)
+	other, _ := relationKey(int64(1))
+	assert.Equal(key, other)
+
+	id := 1
+	other, ok = relationKey(&id)
+	assert.True(ok)
+	assert.Equal(key, other)
+
+	_, ok = relationKey((*int)(nil))
+	assert.False(ok)
+	_, ok = relationKey(nil)
+	assert.False(ok)
+
+	uid := uuid.V4()
+	key, ok = relationKey(uid)
+	assert.True(ok)
+	other, _ = relationKey(uuid.UUID(append([]byte{}, uid...)))
+	assert.Equal(key, other)
+}
+
+func TestInvocationGeneratePreload(t *testing.T) {
+	assert := assert.New(t)
+
+	relations, err := Relations(reflect.TypeOf(relationOrder{}))
+	assert.Nil(err)
+
+	i := defaultDB().Invoke()
+	relation := relations["LineItems"]
+	relatedCols := columnsForType(relation.Type)
+	label, queryBody := i.generatePreload(reflect.TypeOf(relationOrder{}), relation, relatedCols, relatedCols.Lookup()["order_id"], 3)
+	assert.Equal("relation_order_preload_lineitems", label)
+	assert.Equal("SELECT id,order_id,sku FROM relation_line_item WHERE order_id IN ($1,$2,$3) ORDER BY id", queryBody)
+}
+
+func TestInvocationPreloadRelationNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	i := defaultDB().Invoke(OptPreload("NotARelation"))
+	err := i.preload([]reflect.Value{reflect.ValueOf(&relationOrder{}).Elem()})
+	assert.True(IsRelationNotFound(err))
+}
+
+func TestInvocationPreload(t *testing.T) {
+	assert := assert.New(t)
+	tx, err := defaultDB().Begin()
+	assert.Nil(err)
+	defer tx.Rollback()
+
+	invoke := func(options ...InvocationOption) *Invocation {
+		return defaultDB().Invoke(append([]InvocationOption{OptTx(tx)}, options...)...)
+	}
+	assert.Nil(IgnoreExecResult(invoke().Exec("CREATE TABLE relation_customer (id int primary key, name text not null)")))
+	assert.Nil(IgnoreExecResult(invoke().Exec("CREATE TABLE relation_order (id int primary key, customer_id int)")))
+	assert.Nil(IgnoreExecResult(invoke().Exec("CREATE TABLE relation_line_item (id int primary key, order_id int not null, sku text not null)")))
+
+	customerID := 1
+	assert.Nil(invoke().Create(&relationCustomer{ID: customerID, Name: "customer"}))
+	assert.Nil(invoke().CreateMany([]relationOrder{{ID: 1, CustomerID: &customerID}, {ID: 2}, {ID: 3, CustomerID: &customerID}}))
+	assert.Nil(invoke().CreateMany([]relationLineItem{
+		{ID: 1, OrderID: 1, SKU: "a"},
+		{ID: 2, OrderID: 1, SKU: "b"},
+		{ID: 3, OrderID: 3, SKU: "c"},
+	}))
+
+	var orders []relationOrder
+	assert.Nil(invoke(OptPreload("Customer", "LineItems", "Refs", "Latest")).All(&orders))
+	assert.Len(orders, 3)
+	byID := map[int]relationOrder{}
+	for _, order := range orders {
+		byID[order.ID] = order
+	}
+
+	assert.NotNil(byID[1].Customer)
+	assert.Equal("customer", byID[1].Customer.Name)
+	assert.Len(byID[1].LineItems, 2)
+	assert.Equal("a", byID[1].LineItems[0].SKU)
+	assert.Len(byID[1].Refs, 2)
+	assert.NotNil(byID[1].Latest)
+
+	assert.Nil(byID[2].Customer)
+	assert.NotNil(byID[2].LineItems)
+	assert.Empty(byID[2].LineItems)
+	assert.Nil(byID[2].Latest)
+
+	assert.Len(byID[3].LineItems, 1)
+
+	var item relationLineItem
+	found, err := invoke(OptPreload("Order.Customer", "Order.LineItems")).Get(&item, 3)
+	assert.Nil(err)
+	assert.True(found)
+	assert.NotNil(item.Order)
+	assert.Equal(3, item.Order.ID)
+	assert.NotNil(item.Order.Customer)
+	assert.Len(item.Order.LineItems, 1)
+}
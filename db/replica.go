@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+)
+
+// NewReplica returns a new replica for a given driver connection.
+// Replicas start healthy.
+func NewReplica(conn *sql.DB) *Replica {
+	return &Replica{Connection: conn}
+}
+
+// Replica is a read replica connection pool.
+type Replica struct {
+	DSN        string
+	Connection *sql.DB
+
+	unhealthy int32
+}
+
+// Healthy returns if the replica should receive queries.
+func (r *Replica) Healthy() bool {
+	return atomic.LoadInt32(&r.unhealthy) == 0
+}
+
+// SetHealthy sets if the replica should receive queries.
+func (r *Replica) SetHealthy(healthy bool) {
+	if healthy {
+		atomic.StoreInt32(&r.unhealthy, 0)
+		return
+	}
+	atomic.StoreInt32(&r.unhealthy, 1)
+}
+
+// Check pings the replica and updates its health.
+func (r *Replica) Check(ctx context.Context) error {
+	if err := r.Connection.PingContext(ctx); err != nil {
+		r.SetHealthy(false)
+		return Error(err)
+	}
+	r.SetHealthy(true)
+	return nil
+}
+
+// InUse returns the number of driver connections currently in use.
+func (r *Replica) InUse() int {
+	return r.Connection.Stats().InUse
+}
+
+// Close closes the replica connection pool.
+func (r *Replica) Close() error {
+	return r.Connection.Close()
+}
+
+// ReplicaSelector chooses a replica from a list of healthy replicas.
+// The list is never empty.
+type ReplicaSelector func([]*Replica) *Replica
+
+// ReplicaSelectorRoundRobin returns a selector that cycles through replicas in order.
+func ReplicaSelectorRoundRobin() ReplicaSelector {
+	var counter uint64
+	return func(replicas []*Replica) *Replica {
+		next := atomic.AddUint64(&counter, 1) - 1
+		return replicas[next%uint64(len(replicas))]
+	}
+}
+
+// ReplicaSelectorLeastLoaded returns a selector that picks the replica with the
+// fewest driver connections in use, preferring earlier replicas on ties.
+func ReplicaSelectorLeastLoaded() ReplicaSelector {
+	return func(replicas []*Replica) *Replica {
+		selected := replicas[0]
+		selectedInUse := selected.InUse()
+		for _, replica := range replicas[1:] {
+			if inUse := replica.InUse(); inUse < selectedInUse {
+				selected, selectedInUse = replica, inUse
+			}
+		}
+		return selected
+	}
+}
@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+const errRecordingDB ex.Class = "recording db"
+
+// recordingDB is a `DB` that records the statements it is given and fails them.
+type recordingDB struct {
+	Statements []string
+}
+
+func (r *recordingDB) ExecContext(_ context.Context, statement string, _ ...interface{}) (sql.Result, error) {
+	r.Statements = append(r.Statements, statement)
+	return nil, ex.New(errRecordingDB)
+}
+
+func (r *recordingDB) QueryContext(_ context.Context, statement string, _ ...interface{}) (*sql.Rows, error) {
+	r.Statements = append(r.Statements, statement)
+	return nil, ex.New(errRecordingDB)
+}
+
+func (r *recordingDB) QueryRowContext(_ context.Context, statement string, _ ...interface{}) *sql.Row {
+	r.Statements = append(r.Statements, statement)
+	return new(sql.Row)
+}
+
+func newTestReplicas(assert *assert.Assertions, count int) []*Replica {
+	var replicas []*Replica
+	for x := 0; x < count; x++ {
+		conn, err := sql.Open("postgres", fmt.Sprintf("host=replica-%d", x))
+		assert.Nil(err)
+		replicas = append(replicas, NewReplica(conn))
+	}
+	return replicas
+}
+
+func TestReplicaSelectorRoundRobin(t *testing.T) {
+	assert := assert.New(t)
+
+	replicas := newTestReplicas(assert, 3)
+	selector := ReplicaSelectorRoundRobin()
+	assert.Equal(replicas[0], selector(replicas))
+	assert.Equal(replicas[1], selector(replicas))
+	assert.Equal(replicas[2], selector(replicas))
+	assert.Equal(replicas[0], selector(replicas))
+}
+
+func TestReplicaSelectorLeastLoaded(t *testing.T) {
+	assert := assert.New(t)
+
+	replicas := newTestReplicas(assert, 2)
+	selector := ReplicaSelectorLeastLoaded()
+	assert.Equal(replicas[0], selector(replicas))
+	assert.Equal(replicas[1], selector(replicas[1:]))
+}
+
+func TestConnectionSelectReplica(t *testing.T) {
+	assert := assert.New(t)
+
+	conn := &Connection{}
+	assert.Nil(conn.SelectReplica())
+
+	conn.Replicas = newTestReplicas(assert, 2)
+	assert.Equal(conn.Replicas[0], conn.SelectReplica(), "should use the first healthy replica without a selector")
+
+	conn.ReplicaSelector = ReplicaSelectorRoundRobin()
+	conn.Replicas[0].SetHealthy(false)
+	assert.False(conn.Replicas[0].Healthy())
+	assert.Equal(conn.Replicas[1], conn.SelectReplica())
+	assert.Equal(conn.Replicas[1], conn.SelectReplica())
+
+	conn.Replicas[1].SetHealthy(false)
+	assert.Nil(conn.SelectReplica())
+
+	conn.Replicas[0].SetHealthy(true)
+	assert.Equal(conn.Replicas[0], conn.SelectReplica())
+}
+
+func TestConnectionCheckReplicas(t *testing.T) {
+	assert := assert.New(t)
+
+	conn, err := sql.Open("postgres", "host=localhost port=1 connect_timeout=1 sslmode=disable")
+	assert.Nil(err)
+	replica := NewReplica(conn)
+	dbc := &Connection{Replicas: []*Replica{replica}}
+	assert.NotNil(dbc.CheckReplicas(context.Background()))
+	assert.False(replica.Healthy())
+}
+
+func TestInvocationReplicaRouting(t *testing.T) {
+	assert := assert.New(t)
+
+	primary, replica := new(recordingDB), new(recordingDB)
+	newInvocation := func(ctx context.Context, options ...InvocationOption) *Invocation {
+		i := &Invocation{DB: primary, Replica: replica, Context: ctx}
+		for _, option := range options {
+			option(i)
+		}
+		return i
+	}
+
+	_, _ = newInvocation(context.Background()).readQuery("select 1").Any()
+	_, _ = newInvocation(context.Background()).Query("select 2").Any()
+	_, _ = newInvocation(context.Background()).Query("insert into foo default values returning id").Any()
+	_, _ = newInvocation(context.Background()).Exec("update foo")
+	_, _ = newInvocation(context.Background(), OptReadOnly()).Exec("select read_only()")
+	_, _ = newInvocation(context.Background(), OptReadOnly()).Query("select read_only()").Any()
+	_, _ = newInvocation(context.Background(), OptPrimary()).readQuery("select 3").Any()
+	_, _ = newInvocation(context.Background(), OptTx(nil)).Query("select 4").Any()
+	_, _ = newInvocation(context.Background()).Query("select * from foo\nfor  update").Any()
+	assert.Equal([]string{"select 1", "select 2", "select read_only()", "select read_only()", "select 4"}, replica.Statements)
+	assert.Equal([]string{"insert into foo default values returning id", "update foo", "select 3", "select * from foo\nfor  update"}, primary.Statements)
+
+	primary.Statements, replica.Statements = nil, nil
+	ctx := WithReadYourWrites(context.Background())
+	assert.False(HasWritten(ctx))
+	_, _ = newInvocation(ctx).readQuery("select 5").Any()
+	_, _ = newInvocation(ctx).Query("select 6").Any()
+	assert.False(HasWritten(ctx), "plain selects are not writes")
+	_, _ = newInvocation(ctx).Query("update bar set id = 1 returning id").Any()
+	assert.True(HasWritten(ctx))
+	_, _ = newInvocation(ctx).readQuery("select 7").Any()
+	_, _ = newInvocation(context.Background()).readQuery("select 8").Any()
+	assert.Equal([]string{"select 5", "select 6", "select 8"}, replica.Statements)
+	assert.Equal([]string{"update bar set id = 1 returning id", "select 7"}, primary.Statements)
+}
+
+func TestConnectionInvokeSelectsReplicaLazily(t *testing.T) {
+	assert := assert.New(t)
+
+	dbc := &Connection{Replicas: newTestReplicas(assert, 2), ReplicaSelector: ReplicaSelectorRoundRobin()}
+	assert.Nil(dbc.Invoke().Replica, "invoke should not select a replica")
+
+	i := dbc.Invoke()
+	assert.Equal(dbc.Replicas[0].Connection, i.ReadDB())
+	assert.Equal(dbc.Replicas[0].Connection, i.ReadDB(), "the replica should be selected once per invocation")
+	assert.Equal(dbc.Replicas[1].Connection, dbc.Invoke().ReadDB())
+
+	primary := new(recordingDB)
+	assert.Equal(primary, dbc.Invoke(OptDB(primary)).ReadDB())
+	assert.Equal(dbc.Replicas[0].Connection, dbc.Invoke().ReadDB(), "invocations against the primary should not advance the selector")
+}
+
+func TestConnectionOpenInvalidReplica(t *testing.T) {
+	assert := assert.New(t)
+
+	conn := &Connection{Config: Config{Host: "localhost", Database: "postgres", ReplicaDSNs: []string{"postgres://replica-0/postgres", "mysql://replica-1/postgres"}}}
+	assert.NotNil(conn.Open())
+	assert.Nil(conn.Connection)
+	assert.Empty(conn.Replicas)
+
+	conn.Config.ReplicaDSNs = conn.Config.ReplicaDSNs[:1]
+	assert.Nil(conn.Open(), "open should be retryable after a failure")
+	defer conn.Close()
+	assert.NotNil(conn.Connection)
+	assert.Len(conn.Replicas, 1)
+}
@@ -0,0 +1,116 @@
+package db
+
+import "strconv"
+
+var (
+	_ Builder = (*SelectBuilder)(nil)
+)
+
+// Select returns a select builder for the table an object is mapped to.
+// It selects all the object's columns that aren't readonly by default.
+//
+//	var users []User
+//	err := conn.Invoke().QueryBuilder(
+//		db.Select(User{}).Where(db.Eq("email", email)).OrderBy(db.Desc("created_utc")).Limit(10),
+//	).OutMany(&users)
+func Select(object DatabaseMapped) *SelectBuilder {
+	return &SelectBuilder{
+		table:   TableName(object),
+		columns: CachedColumnCollectionFromInstance(object),
+	}
+}
+
+// Order is an order by term.
+type Order struct {
+	Column     string
+	Descending bool
+}
+
+// Asc returns an ascending order by term.
+func Asc(column string) Order {
+	return Order{Column: column}
+}
+
+// Desc returns a descending order by term.
+func Desc(column string) Order {
+	return Order{Column: column, Descending: true}
+}
+
+// SelectBuilder builds a select statement.
+type SelectBuilder struct {
+	table      string
+	columns    *ColumnCollection
+	fields     []string
+	predicates []Predicate
+	orders     []Order
+	limit      int
+	offset     int
+}
+
+// Columns restricts the selected columns.
+func (sb *SelectBuilder) Columns(columns ...string) *SelectBuilder {
+	sb.fields = append(sb.fields, columns...)
+	return sb
+}
+
+// Where adds predicates to the where clause; multiple predicates are joined with `AND`.
+func (sb *SelectBuilder) Where(predicates ...Predicate) *SelectBuilder {
+	sb.predicates = append(sb.predicates, predicates...)
+	return sb
+}
+
+// OrderBy adds order by terms.
+func (sb *SelectBuilder) OrderBy(orders ...Order) *SelectBuilder {
+	sb.orders = append(sb.orders, orders...)
+	return sb
+}
+
+// Limit sets the maximum number of rows returned.
+func (sb *SelectBuilder) Limit(limit int) *SelectBuilder {
+	sb.limit = limit
+	return sb
+}
+
+// Offset sets the number of rows skipped.
+func (sb *SelectBuilder) Offset(offset int) *SelectBuilder {
+	sb.offset = offset
+	return sb
+}
+
+// Label implements Builder.
+func (sb *SelectBuilder) Label() string {
+	return sb.table + "_select"
+}
+
+// Build implements Builder.
+func (sb *SelectBuilder) Build() (string, []interface{}, error) {
+	s := NewStatement(sb.table, sb.columns)
+	s.WriteString("SELECT ")
+	if len(sb.fields) > 0 {
+		s.WriteColumns(sb.fields...)
+	} else {
+		s.WriteColumns(sb.columns.NotReadOnly().ColumnNames()...)
+	}
+	s.WriteString(" FROM " + sb.table)
+	s.WriteWhere(sb.predicates)
+	for index, order := range sb.orders {
+		if index == 0 {
+			s.WriteString(" ORDER BY ")
+		} else {
+			s.WriteString(",")
+		}
+		s.WriteColumn(order.Column)
+		if order.Descending {
+			s.WriteString(" DESC")
+		} else {
+			s.WriteString(" ASC")
+		}
+	}
+	if sb.limit > 0 {
+		s.WriteString(" LIMIT " + strconv.Itoa(sb.limit))
+	}
+	if sb.offset > 0 {
+		s.WriteString(" OFFSET " + strconv.Itoa(sb.offset))
+	}
+	return s.Build()
+}
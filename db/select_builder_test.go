@@ -0,0 +1,32 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestSelectBuilder(t *testing.T) {
+	assert := assert.New(t)
+
+	sb := Select(builderTestObj{})
+	assert.Equal("builder_test_select", sb.Label())
+	statement, args, err := sb.Build()
+	assert.Nil(err)
+	assert.Equal("SELECT id,email,name,meta FROM builder_test", statement)
+	assert.Empty(args)
+
+	statement, args, err = Select(&builderTestObj{}).
+		Columns("id", "email").
+		Where(Eq("email", "foo@bar.com"), Gt("id", 10)).
+		OrderBy(Desc("id"), Asc("name")).
+		Limit(10).
+		Offset(20).
+		Build()
+	assert.Nil(err)
+	assert.Equal("SELECT id,email FROM builder_test WHERE (email = $1) AND (id > $2) ORDER BY id DESC,name ASC LIMIT 10 OFFSET 20", statement)
+	assert.Equal([]interface{}{"foo@bar.com", 10}, args)
+
+	_, _, err = Select(builderTestObj{}).OrderBy(Asc("nmae")).Build()
+	assert.True(IsColumnNotFound(err))
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/blend/go-sdk/ansi"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/stringutil"
+	"github.com/blend/go-sdk/timeutil"
+)
+
+// Logger flags
+const (
+	SlowQueryFlag = "db.query.slow"
+)
+
+// these are compile time assertions
+var (
+	_ logger.Event        = (*SlowQueryEvent)(nil)
+	_ logger.TextWritable = (*SlowQueryEvent)(nil)
+	_ logger.JSONWritable = (*SlowQueryEvent)(nil)
+)
+
+// NewSlowQueryEvent returns a new slow query event for a query event that exceeded a threshold.
+func NewSlowQueryEvent(qe QueryEvent, threshold time.Duration) SlowQueryEvent {
+	return SlowQueryEvent{
+		QueryEvent: qe,
+		Threshold:  threshold,
+	}
+}
+
+// NewSlowQueryEventListener returns a new listener for slow query events.
+func NewSlowQueryEventListener(listener func(context.Context, SlowQueryEvent)) logger.Listener {
+	return func(ctx context.Context, e logger.Event) {
+		if typed, isTyped := e.(SlowQueryEvent); isTyped {
+			listener(ctx, typed)
+		}
+	}
+}
+
+// SlowQueryEvent is a query that took longer than a threshold.
+type SlowQueryEvent struct {
+	QueryEvent
+	Threshold time.Duration
+}
+
+// GetFlag implements Event.
+func (e SlowQueryEvent) GetFlag() string { return SlowQueryFlag }
+
+// WriteText writes the event text to the output.
+func (e SlowQueryEvent) WriteText(tf logger.TextFormatter, wr io.Writer) {
+	if len(e.Label) > 0 {
+		io.WriteString(wr, fmt.Sprintf("[%s]", tf.Colorize(e.Label, ansi.ColorLightWhite)))
+		io.WriteString(wr, logger.Space)
+	}
+	io.WriteString(wr, tf.Colorize(e.Elapsed.String(), ansi.ColorYellow))
+	io.WriteString(wr, " > ")
+	io.WriteString(wr, e.Threshold.String())
+	if len(e.Body) > 0 {
+		io.WriteString(wr, logger.Space)
+		io.WriteString(wr, stringutil.CompressSpace(e.Body))
+	}
+}
+
+// Decompose implements JSONWritable.
+func (e SlowQueryEvent) Decompose() map[string]interface{} {
+	output := e.QueryEvent.Decompose()
+	output["threshold"] = timeutil.Milliseconds(e.Threshold)
+	return output
+}
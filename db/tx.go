@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/retry"
+)
+
+// TxAction is an action run in a transaction.
+type TxAction func(*Invocation) error
+
+// InTx runs an action in a transaction.
+//
+// The transaction is committed if the action returns nil, and rolled back if the action
+// returns an error or panics. Serialization failures and deadlocks (postgres error codes
+// `40001` and `40P01`) are retried with backoff, re-running the action in a new transaction,
+// so the action should not have side effects outside the transaction.
+//
+// If the context is already in a transaction started by `InTx`, the action is run in a
+// savepoint of that transaction instead; an error rolls back to the savepoint, and retries
+// are left to the outermost transaction.
+func (dbc *Connection) InTx(ctx context.Context, action TxAction, opts ...TxOption) error {
+	if dbc.Connection == nil {
+		return ex.New(ErrConnectionClosed)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	options := TxOptions{
+		MaxAttempts: DefaultTxMaxAttempts,
+		RetryDelay:  DefaultTxRetryDelay,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = 1
+	}
+
+	if state := txStateFromContext(ctx); state != nil {
+		return dbc.inSavepoint(ctx, state, action, options)
+	}
+
+	_, err := retry.Retry(ctx, func(ctx context.Context) (interface{}, error) {
+		return nil, dbc.inTx(ctx, action, options)
+	},
+		retry.OptMaxAttempts(options.MaxAttempts),
+		retry.OptExponentialDelay(options.RetryDelay),
+		retry.OptShouldRetryProvider(IsTxRetryable),
+	)
+	return err
+}
+
+func (dbc *Connection) inTx(ctx context.Context, action TxAction, options TxOptions) (err error) {
+	var tx *sql.Tx
+	tx, err = dbc.BeginContext(ctx, func(txo *sql.TxOptions) {
+		txo.Isolation = options.Isolation
+		txo.ReadOnly = options.ReadOnly
+	})
+	if err != nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ex.Nest(ex.New(r), Error(tx.Rollback()))
+			return
+		}
+		if err != nil {
+			err = ex.Nest(err, Error(tx.Rollback()))
+			return
+		}
+		err = Error(tx.Commit())
+	}()
+
+	txCtx := withTxState(ctx, &txState{Tx: tx})
+	err = action(dbc.Invoke(append([]InvocationOption{OptContext(txCtx), OptTx(tx)}, options.InvocationOptions...)...))
+	return
+}
+
+func (dbc *Connection) inSavepoint(ctx context.Context, state *txState, action TxAction, options TxOptions) (err error) {
+	savepoint := fmt.Sprintf("sp_%d", atomic.AddInt32(&state.Savepoints, 1))
+	if _, err = state.Tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		err = Error(err)
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ex.New(r)
+		}
+		if err != nil {
+			_, rollbackErr := state.Tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+			err = ex.Nest(err, Error(rollbackErr))
+			return
+		}
+		_, err = state.Tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+		err = Error(err)
+	}()
+	err = action(dbc.Invoke(append([]InvocationOption{OptContext(ctx), OptTx(state.Tx)}, options.InvocationOptions...)...))
+	return
+}
+
+type txStateKey struct{}
+
+// txState is the transaction an `InTx` context is running in.
+type txState struct {
+	Tx         *sql.Tx
+	Savepoints int32
+}
+
+func withTxState(ctx context.Context, state *txState) context.Context {
+	return context.WithValue(ctx, txStateKey{}, state)
+}
+
+func txStateFromContext(ctx context.Context) *txState {
+	if state, ok := ctx.Value(txStateKey{}).(*txState); ok {
+		return state
+	}
+	return nil
+}
+
+// TxFromContext returns the transaction a context passed to an `InTx` action is running in,
+// or nil if it is not in a transaction.
+func TxFromContext(ctx context.Context) *sql.Tx {
+	if ctx == nil {
+		return nil
+	}
+	if state := txStateFromContext(ctx); state != nil {
+		return state.Tx
+	}
+	return nil
+}
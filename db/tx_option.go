@@ -0,0 +1,49 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// TxOptions are options for `InTx`.
+type TxOptions struct {
+	// Isolation is the transaction isolation level.
+	Isolation sql.IsolationLevel
+	// ReadOnly marks the transaction as read only.
+	ReadOnly bool
+	// MaxAttempts is the maximum number of times the transaction is attempted.
+	MaxAttempts uint
+	// RetryDelay is the base delay between attempts; it doubles each attempt.
+	RetryDelay time.Duration
+	// InvocationOptions are applied to the invocation passed to the action.
+	InvocationOptions []InvocationOption
+}
+
+// TxOption mutates transaction options.
+type TxOption func(*TxOptions)
+
+// OptTxIsolation sets the transaction isolation level.
+func OptTxIsolation(level sql.IsolationLevel) TxOption {
+	return func(txo *TxOptions) { txo.Isolation = level }
+}
+
+// OptTxReadOnly marks the transaction as read only.
+func OptTxReadOnly() TxOption {
+	return func(txo *TxOptions) { txo.ReadOnly = true }
+}
+
+// OptTxMaxAttempts sets the maximum number of times the transaction is attempted.
+// Set it to 1 to disable retries; the transaction is always attempted at least once.
+func OptTxMaxAttempts(maxAttempts uint) TxOption {
+	return func(txo *TxOptions) { txo.MaxAttempts = maxAttempts }
+}
+
+// OptTxRetryDelay sets the base delay between attempts.
+func OptTxRetryDelay(d time.Duration) TxOption {
+	return func(txo *TxOptions) { txo.RetryDelay = d }
+}
+
+// OptTxInvocationOptions sets options applied to the invocation passed to the action.
+func OptTxInvocationOptions(options ...InvocationOption) TxOption {
+	return func(txo *TxOptions) { txo.InvocationOptions = append(txo.InvocationOptions, options...) }
+}
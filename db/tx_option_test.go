@@ -0,0 +1,29 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestTxOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	var txo TxOptions
+	OptTxIsolation(sql.LevelSerializable)(&txo)
+	assert.Equal(sql.LevelSerializable, txo.Isolation)
+
+	OptTxReadOnly()(&txo)
+	assert.True(txo.ReadOnly)
+
+	OptTxMaxAttempts(5)(&txo)
+	assert.Equal(uint(5), txo.MaxAttempts)
+
+	OptTxRetryDelay(time.Millisecond)(&txo)
+	assert.Equal(time.Millisecond, txo.RetryDelay)
+
+	OptTxInvocationOptions(OptLabel("foo"), OptReadOnly())(&txo)
+	assert.Len(txo.InvocationOptions, 2)
+}
@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/uuid"
+)
+
+func createTxTestTable(assert *assert.Assertions) (tableName string, drop func()) {
+	tableName = fmt.Sprintf("tx_test_%s", uuid.V4().String())
+	assert.Nil(IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("CREATE TABLE %q (id int not null primary key, name varchar(255) not null)", tableName))))
+	return tableName, func() {
+		_, _ = defaultDB().Exec(fmt.Sprintf("DROP TABLE IF EXISTS %q", tableName))
+	}
+}
+
+func countTxTestRows(assert *assert.Assertions, tableName string) (count int) {
+	_, err := defaultDB().Query(fmt.Sprintf("SELECT count(*) FROM %q", tableName)).Scan(&count)
+	assert.Nil(err)
+	return
+}
+
+func TestConnectionInTx(t *testing.T) {
+	assert := assert.New(t)
+
+	tableName, drop := createTxTestTable(assert)
+	defer drop()
+	insert := fmt.Sprintf("INSERT INTO %q (id, name) VALUES ($1, $2)", tableName)
+
+	err := defaultDB().InTx(context.Background(), func(inv *Invocation) error {
+		assert.NotNil(TxFromContext(inv.Context))
+		return IgnoreExecResult(inv.Exec(insert, 1, "one"))
+	}, OptTxIsolation(sql.LevelSerializable))
+	assert.Nil(err)
+	assert.Equal(1, countTxTestRows(assert, tableName))
+
+	err = defaultDB().InTx(context.Background(), func(inv *Invocation) error {
+		if err := IgnoreExecResult(inv.Exec(insert, 2, "two")); err != nil {
+			return err
+		}
+		return ex.New("this is only a test")
+	})
+	assert.True(ex.Is(err, ex.Class("this is only a test")))
+	assert.Equal(1, countTxTestRows(assert, tableName), "should roll back on error")
+
+	err = defaultDB().InTx(context.Background(), func(inv *Invocation) error {
+		if err := IgnoreExecResult(inv.Exec(insert, 3, "three")); err != nil {
+			return err
+		}
+		panic("this is only a test")
+	})
+	assert.NotNil(err)
+	assert.Equal(1, countTxTestRows(assert, tableName), "should roll back on panic")
+}
+
+func TestConnectionInTxRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	var attempts int
+	err := defaultDB().InTx(context.Background(), func(inv *Invocation) error {
+		attempts++
+		if attempts < 2 {
+			return &pq.Error{Code: PQCodeSerializationFailure}
+		}
+		return nil
+	}, OptTxRetryDelay(time.Millisecond))
+	assert.Nil(err)
+	assert.Equal(2, attempts)
+
+	attempts = 0
+	err = defaultDB().InTx(context.Background(), func(inv *Invocation) error {
+		attempts++
+		return &pq.Error{Code: PQCodeDeadlockDetected}
+	}, OptTxRetryDelay(time.Millisecond), OptTxMaxAttempts(2))
+	assert.True(IsDeadlockDetected(err))
+	assert.Equal(2, attempts)
+
+	attempts = 0
+	err = defaultDB().InTx(context.Background(), func(inv *Invocation) error {
+		attempts++
+		return &pq.Error{Code: "23505"}
+	}, OptTxRetryDelay(time.Millisecond))
+	assert.NotNil(err)
+	assert.Equal(1, attempts, "should not retry other errors")
+}
+
+func TestConnectionInTxSavepoint(t *testing.T) {
+	assert := assert.New(t)
+
+	tableName, drop := createTxTestTable(assert)
+	defer drop()
+	insert := fmt.Sprintf("INSERT INTO %q (id, name) VALUES ($1, $2)", tableName)
+
+	err := defaultDB().InTx(context.Background(), func(inv *Invocation) error {
+		outer := TxFromContext(inv.Context)
+		if err := IgnoreExecResult(inv.Exec(insert, 1, "one")); err != nil {
+			return err
+		}
+		nestedErr := defaultDB().InTx(inv.Context, func(nested *Invocation) error {
+			assert.Equal(outer, TxFromContext(nested.Context))
+			if err := IgnoreExecResult(nested.Exec(insert, 2, "two")); err != nil {
+				return err
+			}
+			return ex.New("this is only a test")
+		})
+		assert.NotNil(nestedErr)
+		return defaultDB().InTx(inv.Context, func(nested *Invocation) error {
+			return IgnoreExecResult(nested.Exec(insert, 3, "three"))
+		})
+	})
+	assert.Nil(err)
+
+	var ids []int
+	assert.Nil(defaultDB().Query(fmt.Sprintf("SELECT id FROM %q ORDER BY id", tableName)).Each(func(r Rows) error {
+		var id int
+		if err := r.Scan(&id); err != nil {
+			return err
+		}
+		ids = append(ids, id)
+		return nil
+	}))
+	assert.Equal([]int{1, 3}, ids)
+}
+
+func TestConnectionInTxClosed(t *testing.T) {
+	assert := assert.New(t)
+
+	conn, err := New()
+	assert.Nil(err)
+	err = conn.InTx(context.Background(), func(_ *Invocation) error { return nil })
+	assert.True(IsConnectionClosed(err))
+}
+
+func TestConnectionInTxZeroMaxAttempts(t *testing.T) {
+	assert := assert.New(t)
+
+	driverConn, err := sql.Open("postgres", "host=127.0.0.1 port=1 sslmode=disable connect_timeout=1")
+	assert.Nil(err)
+	conn := &Connection{Connection: driverConn}
+	defer conn.Close()
+
+	// the transaction is attempted once, so the failure to begin it is returned rather than nothing happening.
+	err = conn.InTx(context.Background(), func(_ *Invocation) error { return nil }, OptTxMaxAttempts(0))
+	assert.NotNil(err)
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"encoding/json"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ Builder = (*UpdateBuilder)(nil)
+)
+
+// Update returns an update builder for the table an object is mapped to.
+//
+//	res, err := conn.Invoke().ExecBuilder(
+//		db.Update(User{}).Set("email", email).Where(db.Eq("id", id)),
+//	)
+func Update(object DatabaseMapped) *UpdateBuilder {
+	return &UpdateBuilder{
+		table:   TableName(object),
+		columns: CachedColumnCollectionFromInstance(object),
+	}
+}
+
+// UpdateBuilder builds an update statement.
+type UpdateBuilder struct {
+	table      string
+	columns    *ColumnCollection
+	sets       []updateSet
+	predicates []Predicate
+	returning  []string
+}
+
+type updateSet struct {
+	Column string
+	Value  interface{}
+}
+
+// Set adds a column to set to a given value.
+// Values for json columns are serialized.
+func (ub *UpdateBuilder) Set(column string, value interface{}) *UpdateBuilder {
+	ub.sets = append(ub.sets, updateSet{Column: column, Value: value})
+	return ub
+}
+
+// Where adds predicates to the where clause; multiple predicates are joined with `AND`.
+func (ub *UpdateBuilder) Where(predicates ...Predicate) *UpdateBuilder {
+	ub.predicates = append(ub.predicates, predicates...)
+	return ub
+}
+
+// Returning sets the columns returned by the statement.
+func (ub *UpdateBuilder) Returning(columns ...string) *UpdateBuilder {
+	ub.returning = append(ub.returning, columns...)
+	return ub
+}
+
+// Label implements Builder.
+func (ub *UpdateBuilder) Label() string {
+	return ub.table + "_update_where"
+}
+
+// Build implements Builder.
+func (ub *UpdateBuilder) Build() (string, []interface{}, error) {
+	if len(ub.sets) == 0 {
+		return "", nil, ex.New(ErrUpdateNoValues, ex.OptMessagef("table: %s", ub.table))
+	}
+
+	s := NewStatement(ub.table, ub.columns)
+	s.WriteString("UPDATE " + ub.table + " SET ")
+	lookup := ub.columns.Lookup()
+	for index, set := range ub.sets {
+		if index > 0 {
+			s.WriteString(",")
+		}
+		s.WriteColumn(set.Column)
+		s.WriteString(" = ")
+		value := set.Value
+		if col, ok := lookup[set.Column]; ok && col.IsJSON && !isNil(value) {
+			contents, err := json.Marshal(value)
+			if err != nil {
+				return "", nil, ex.New(err, ex.OptMessagef("column: %s", set.Column))
+			}
+			value = string(contents)
+		}
+		s.WriteArg(value)
+	}
+	s.WriteWhere(ub.predicates)
+	s.WriteReturning(ub.returning)
+	return s.Build()
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestUpdateBuilder(t *testing.T) {
+	assert := assert.New(t)
+
+	ub := Update(builderTestObj{})
+	assert.Equal("builder_test_update_where", ub.Label())
+	_, _, err := ub.Build()
+	assert.True(ex.Is(err, ErrUpdateNoValues))
+
+	statement, args, err := Update(builderTestObj{}).
+		Set("name", "foo").
+		Set("meta", map[string]string{"foo": "bar"}).
+		Where(Eq("id", 1)).
+		Returning("id", "name").
+		Build()
+	assert.Nil(err)
+	assert.Equal("UPDATE builder_test SET name = $1,meta = $2 WHERE id = $3 RETURNING id,name", statement)
+	assert.Equal([]interface{}{"foo", `{"foo":"bar"}`, 1}, args)
+
+	statement, args, err = Update(builderTestObj{}).Set("meta", nil).Build()
+	assert.Nil(err)
+	assert.Equal("UPDATE builder_test SET meta = $1", statement)
+	assert.Equal([]interface{}{nil}, args)
+
+	_, _, err = Update(builderTestObj{}).Set("name", "foo").Returning("nmae").Build()
+	assert.True(IsColumnNotFound(err))
+}
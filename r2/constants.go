@@ -22,6 +22,36 @@ const (
 	HeaderConnection = "Connection"
 	// HeaderContentType is a http header.
 	HeaderContentType = "Content-Type"
+	// HeaderHost is a http header.
+	HeaderHost = "Host"
+	// HeaderAuthorization is a http header.
+	HeaderAuthorization = "Authorization"
+	// HeaderCookie is a http header.
+	HeaderCookie = "Cookie"
+	// HeaderAcceptEncoding is a http header.
+	HeaderAcceptEncoding = "Accept-Encoding"
+	// HeaderContentEncoding is a http header.
+	HeaderContentEncoding = "Content-Encoding"
+	// HeaderContentLength is a http header.
+	HeaderContentLength = "Content-Length"
+)
+
+const (
+	// HeaderSignature is the header the hmac signer writes the signature to.
+	HeaderSignature = "X-Signature"
+	// HeaderSignatureTimestamp is the header the hmac signer writes the signing time to, in unix seconds.
+	HeaderSignatureTimestamp = "X-Signature-Timestamp"
+	// HeaderSignatureNonce is the header the hmac signer writes a unique per request value to.
+	HeaderSignatureNonce = "X-Signature-Nonce"
+	// HeaderContentSHA256 is the header the hmac signer writes the hex sha256 of the body to.
+	HeaderContentSHA256 = "X-Content-Sha256"
+)
+
+const (
+	// SignatureAlgorithmHMACSHA256 is a signature algorithm.
+	SignatureAlgorithmHMACSHA256 = "hmac-sha256"
+	// SignatureAlgorithmHMACSHA512 is a signature algorithm.
+	SignatureAlgorithmHMACSHA512 = "hmac-sha512"
 )
 
 const (
@@ -29,6 +59,15 @@ const (
 	ConnectionKeepAlive = "keep-alive"
 )
 
+const (
+	// ContentEncodingIdentity is the identity (uncompressed) content encoding.
+	ContentEncodingIdentity = webutil.ContentEncodingIdentity
+	// ContentEncodingGZIP is the gzip content encoding.
+	ContentEncodingGZIP = webutil.ContentEncodingGZIP
+	// ContentEncodingDeflate is the deflate (zlib) content encoding.
+	ContentEncodingDeflate = "deflate"
+)
+
 const (
 	// ContentTypeApplicationJSON is a content type header value.
 	ContentTypeApplicationJSON = webutil.ContentTypeApplicationJSON
@@ -0,0 +1,193 @@
+package r2
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+const (
+	// CurlRedacted is the value secrets are replaced with when rendering a redacted curl command.
+	CurlRedacted = "REDACTED"
+)
+
+// DefaultCurlRedactHeaders are the headers redacted by `OptCurlRedact`.
+var DefaultCurlRedactHeaders = []string{
+	HeaderAuthorization,
+	HeaderCookie,
+	"Proxy-Authorization",
+	HeaderSignature,
+	HeaderAmzSecurityToken,
+}
+
+// CurlOptions are options for rendering a request as a curl command.
+type CurlOptions struct {
+	// RedactHeaders are header names whose values are replaced with `CurlRedacted`.
+	RedactHeaders []string
+	// RedactQuery are query parameter names whose values are replaced with `CurlRedacted`.
+	RedactQuery []string
+	// RedactUserInfo replaces the password of any url user info with `CurlRedacted`.
+	RedactUserInfo bool
+}
+
+// CurlOption mutates curl options.
+type CurlOption func(*CurlOptions)
+
+// OptCurlRedact redacts the default set of secret headers and url passwords.
+func OptCurlRedact() CurlOption {
+	return func(co *CurlOptions) {
+		co.RedactHeaders = append(co.RedactHeaders, DefaultCurlRedactHeaders...)
+		co.RedactUserInfo = true
+	}
+}
+
+// OptCurlRedactHeaders redacts the values of the given headers.
+func OptCurlRedactHeaders(headers ...string) CurlOption {
+	return func(co *CurlOptions) {
+		co.RedactHeaders = append(co.RedactHeaders, headers...)
+	}
+}
+
+// OptCurlRedactQuery redacts the values of the given query parameters.
+func OptCurlRedactQuery(keys ...string) CurlOption {
+	return func(co *CurlOptions) {
+		co.RedactQuery = append(co.RedactQuery, keys...)
+	}
+}
+
+// Curl returns the request as an equivalent curl command.
+//
+// The body, if any, is read in full and replaced with an in memory copy so the
+// request can still be sent afterwards.
+// Client certificates and custom root cas cannot be represented and are omitted.
+func (r *Request) Curl(options ...CurlOption) (string, error) {
+	if r.Err != nil {
+		return "", r.Err
+	}
+	var co CurlOptions
+	for _, option := range options {
+		option(&co)
+	}
+
+	args := []string{"curl"}
+	if method := r.Request.Method; method != "" && method != MethodGet {
+		args = append(args, "-X", method)
+	}
+	args = append(args, curlTLSFlags(r.Client)...)
+	if r.Client == nil || r.Client.CheckRedirect == nil {
+		args = append(args, "-L")
+	}
+
+	if r.Request.Host != "" {
+		args = append(args, "-H", shellQuote(HeaderHost+": "+r.Request.Host))
+	}
+	headerKeys := make([]string, 0, len(r.Request.Header))
+	for key := range r.Request.Header {
+		headerKeys = append(headerKeys, key)
+	}
+	sort.Strings(headerKeys)
+	for _, key := range headerKeys {
+		for _, value := range r.Request.Header[key] {
+			if curlShouldRedact(co.RedactHeaders, key) {
+				value = CurlRedacted
+			}
+			args = append(args, "-H", shellQuote(key+": "+value))
+		}
+	}
+
+	body, err := r.curlBody()
+	if err != nil {
+		return "", err
+	}
+	if body != nil {
+		args = append(args, "--data-binary", shellQuote(string(body)))
+	}
+
+	args = append(args, shellQuote(curlURL(r.Request.URL, co)))
+	return strings.Join(args, " "), nil
+}
+
+// curlBody reads the body, restoring it after, and reconciles post form values.
+func (r *Request) curlBody() ([]byte, error) {
+	if r.Request.Body != nil && r.Request.Body != http.NoBody {
+		contents, err := ioutil.ReadAll(r.Request.Body)
+		if err != nil {
+			return nil, ex.New(err)
+		}
+		_ = r.Request.Body.Close()
+		r.Request.Body = ioutil.NopCloser(bytes.NewReader(contents))
+		return contents, nil
+	}
+	if len(r.Request.PostForm) > 0 {
+		return []byte(r.Request.PostForm.Encode()), nil
+	}
+	return nil, nil
+}
+
+func curlTLSFlags(client *http.Client) (flags []string) {
+	if client == nil {
+		return
+	}
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok || transport.TLSClientConfig == nil {
+		return
+	}
+	if transport.TLSClientConfig.InsecureSkipVerify {
+		flags = append(flags, "--insecure")
+	}
+	switch transport.TLSClientConfig.MinVersion {
+	case tls.VersionTLS10:
+		flags = append(flags, "--tlsv1.0")
+	case tls.VersionTLS11:
+		flags = append(flags, "--tlsv1.1")
+	case tls.VersionTLS12:
+		flags = append(flags, "--tlsv1.2")
+	case tls.VersionTLS13:
+		flags = append(flags, "--tlsv1.3")
+	}
+	return
+}
+
+func curlURL(u *url.URL, co CurlOptions) string {
+	if u == nil {
+		return ""
+	}
+	output := *u
+	if co.RedactUserInfo && output.User != nil {
+		if _, hasPassword := output.User.Password(); hasPassword {
+			output.User = url.UserPassword(output.User.Username(), CurlRedacted)
+		}
+	}
+	if len(co.RedactQuery) > 0 {
+		query := output.Query()
+		for key, values := range query {
+			if curlShouldRedact(co.RedactQuery, key) {
+				for index := range values {
+					values[index] = CurlRedacted
+				}
+			}
+		}
+		output.RawQuery = query.Encode()
+	}
+	return output.String()
+}
+
+func curlShouldRedact(names []string, name string) bool {
+	for _, redacted := range names {
+		if strings.EqualFold(redacted, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// shellQuote single quotes a value for a posix shell.
+func shellQuote(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
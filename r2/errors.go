@@ -8,4 +8,26 @@ import (
 const (
 	ErrNoContentJSON ex.Class = "server returned an http 204 for a request expecting json"
 	ErrNoContentXML  ex.Class = "server returned an http 204 for a request expecting xml"
+
+	ErrSignatureMissing          ex.Class = "request signature is missing"
+	ErrSignatureMalformed        ex.Class = "request signature is malformed"
+	ErrSignatureInvalid          ex.Class = "request signature is invalid"
+	ErrSignatureAlgorithmUnknown ex.Class = "request signature algorithm is unknown"
+	ErrSignatureHeaderNotSigned  ex.Class = "request signature does not cover a required header"
+
+	ErrOAuth2TokenRequest ex.Class = "oauth2 token request failed"
+
+	ErrContentEncodingUnsupported ex.Class = "content encoding is unsupported"
 )
+
+// IsErrSignature returns if an error is one of the request signature verification errors.
+func IsErrSignature(err error) bool {
+	if err == nil {
+		return false
+	}
+	return ex.Is(err, ErrSignatureMissing) ||
+		ex.Is(err, ErrSignatureMalformed) ||
+		ex.Is(err, ErrSignatureInvalid) ||
+		ex.Is(err, ErrSignatureAlgorithmUnknown) ||
+		ex.Is(err, ErrSignatureHeaderNotSigned)
+}
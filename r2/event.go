@@ -41,6 +41,10 @@ type Event struct {
 	Body []byte
 	// Elapsed is the time elapsed.
 	Elapsed time.Duration
+	// ContentEncoding is the original content encoding of a response decoded by `OptDecompress`.
+	ContentEncoding string
+	// CompressedContentLength is the compressed size of a response decoded by `OptDecompress`.
+	CompressedContentLength int64
 }
 
 // GetFlag implements logger.Event.
@@ -74,7 +78,7 @@ func (e *Event) Decompose() map[string]interface{} {
 		}
 	}
 	if e.Response != nil {
-		output["res"] = map[string]interface{}{
+		res := map[string]interface{}{
 			"statusCode":      e.Response.StatusCode,
 			"contentLength":   e.Response.ContentLength,
 			"contentType":     tryHeader(e.Response.Header, "Content-Type", "content-type"),
@@ -83,6 +87,11 @@ func (e *Event) Decompose() map[string]interface{} {
 			"cert":            webutil.ParseCertInfo(e.Response),
 			"elapsed":         timeutil.Milliseconds(e.Elapsed),
 		}
+		if e.ContentEncoding != "" {
+			res["contentEncoding"] = e.ContentEncoding
+			res["compressedContentLength"] = e.CompressedContentLength
+		}
+		output["res"] = res
 	}
 	if e.Body != nil {
 		output["body"] = string(e.Body)
@@ -16,9 +16,25 @@ func OptEventRequest(req *http.Request) EventOption {
 }
 
 // OptEventResponse sets the response.
+// If the response body was decoded by `OptDecompress` it also sets the original content encoding and compressed size.
 func OptEventResponse(res *http.Response) EventOption {
 	return func(e *Event) {
 		e.Response = res
+		if res == nil {
+			return
+		}
+		if decoded, ok := res.Body.(*DecodedBody); ok {
+			e.ContentEncoding = decoded.Encoding
+			e.CompressedContentLength = decoded.CompressedSize()
+		}
+	}
+}
+
+// OptEventContentEncoding sets the original content encoding and compressed size of the response.
+func OptEventContentEncoding(encoding string, compressedContentLength int64) EventOption {
+	return func(e *Event) {
+		e.ContentEncoding = encoding
+		e.CompressedContentLength = compressedContentLength
 	}
 }
 
@@ -0,0 +1,109 @@
+package r2
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestHMACSignerSign(t *testing.T) {
+	assert := assert.New(t)
+
+	signer := HMACSigner{
+		KeyID: "test-key",
+		Key:   []byte("a secret key"),
+		Now:   func() time.Time { return time.Date(2019, 10, 04, 12, 00, 00, 00, time.UTC) },
+	}
+
+	req := New("http://foo.bar.local/buzz?b=2&a=1", OptPost(), OptBodyBytes([]byte("hello")))
+	assert.Nil(req.Err)
+	assert.Nil(signer.Sign(&req.Request))
+
+	assert.Equal("1570190400", req.Header.Get(HeaderSignatureTimestamp))
+	assert.NotEmpty(req.Header.Get(HeaderSignatureNonce))
+	assert.Equal("2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", req.Header.Get(HeaderContentSHA256))
+
+	signature, err := ParseHMACSignature(req.Header.Get(HeaderSignature))
+	assert.Nil(err)
+	assert.Equal("test-key", signature.KeyID)
+	assert.Equal(SignatureAlgorithmHMACSHA256, signature.Algorithm)
+	assert.Equal([]string{"host", "x-content-sha256", "x-signature-nonce", "x-signature-timestamp"}, signature.Headers)
+
+	// the body should still be readable after signing
+	body, err := ioutil.ReadAll(req.Body)
+	assert.Nil(err)
+	assert.Equal("hello", string(body))
+}
+
+func TestHMACSignerVerify(t *testing.T) {
+	assert := assert.New(t)
+
+	signer := HMACSigner{KeyID: "test-key", Key: []byte("a secret key"), Algorithm: SignatureAlgorithmHMACSHA512}
+
+	var verifyErr error
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		signature, err := ParseHMACSignature(r.Header.Get(HeaderSignature))
+		if err != nil {
+			verifyErr = err
+		} else {
+			verifyErr = HMACSigner{Key: []byte("a secret key")}.Verify(r, signature)
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL+"/foo?bar=baz", OptPost(), OptBodyBytes([]byte("hello")), OptSigner(signer)).Discard()
+	assert.Nil(err)
+	assert.Nil(verifyErr)
+
+	_, err = New(server.URL+"/foo?bar=baz", OptPost(), OptBodyBytes([]byte("hello")), OptSigner(signer), OptOnRequest(func(r *http.Request) error {
+		r.Body = ioutil.NopCloser(bytes.NewReader([]byte("tampered")))
+		r.ContentLength = 8
+		return nil
+	})).Discard()
+	assert.Nil(err)
+	assert.True(IsErrSignature(verifyErr))
+
+	_, err = New(server.URL+"/foo?bar=baz", OptSigner(HMACSigner{KeyID: "test-key", Key: []byte("wrong key")})).Discard()
+	assert.Nil(err)
+	assert.True(IsErrSignature(verifyErr))
+}
+
+func TestHMACSignerVerifyRequiresHeaders(t *testing.T) {
+	assert := assert.New(t)
+
+	req := New("http://foo.bar.local/")
+	err := HMACSigner{Key: []byte("a secret key")}.Verify(&req.Request, HMACSignature{
+		KeyID:     "test-key",
+		Algorithm: SignatureAlgorithmHMACSHA256,
+		Headers:   []string{"host"},
+		Signature: []byte("garbage"),
+	})
+	assert.True(IsErrSignature(err))
+}
+
+func TestParseHMACSignature(t *testing.T) {
+	assert := assert.New(t)
+
+	expected := HMACSignature{
+		KeyID:     "test-key",
+		Algorithm: SignatureAlgorithmHMACSHA256,
+		Headers:   []string{"host", "x-signature-nonce"},
+		Signature: []byte("a signature"),
+	}
+	parsed, err := ParseHMACSignature(expected.String())
+	assert.Nil(err)
+	assert.Equal(expected, parsed)
+
+	_, err = ParseHMACSignature("")
+	assert.True(IsErrSignature(err))
+	_, err = ParseHMACSignature("garbage")
+	assert.True(IsErrSignature(err))
+	_, err = ParseHMACSignature(`keyId="test-key",algorithm="hmac-sha256",signature="!!"`)
+	assert.True(IsErrSignature(err))
+}
@@ -0,0 +1,158 @@
+package r2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/configutil"
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ OAuth2TokenSource = (*OAuth2ClientCredentials)(nil)
+)
+
+const (
+	// DefaultOAuth2ExpiryDelta is the default time before expiry that tokens are refreshed.
+	DefaultOAuth2ExpiryDelta = 30 * time.Second
+)
+
+// OAuth2ClientCredentials is a concurrency safe oauth2 client credentials token source.
+//
+// Tokens are fetched with r2, cached until shortly before they expire, and refreshed
+// by a single caller at a time; concurrent callers wait for the in flight refresh.
+// A single value should be shared by all requests that use the same credentials.
+type OAuth2ClientCredentials struct {
+	// TokenURL is the token endpoint.
+	TokenURL string
+	// ClientID is the client id.
+	// Use `configutil.Env(...)`, `configutil.String(...)` or `secrets.StringSource{...}` to provide it.
+	ClientID configutil.StringSource
+	// ClientSecret is the client secret.
+	ClientSecret configutil.StringSource
+	// Scopes are the requested scopes.
+	Scopes []string
+	// Params are additional form parameters sent to the token endpoint, e.g. `audience`.
+	Params url.Values
+	// ExpiryDelta is how long before expiry tokens are refreshed.
+	// It defaults to `DefaultOAuth2ExpiryDelta`.
+	ExpiryDelta time.Duration
+	// Options are additional options for the token request, e.g. timeouts or tls settings.
+	Options []Option
+	// Now returns the current time, it defaults to `time.Now`.
+	Now func() time.Time
+
+	mu    sync.Mutex
+	token *OAuth2Token
+}
+
+// ExpiryDeltaOrDefault returns the expiry delta or a default.
+func (cc *OAuth2ClientCredentials) ExpiryDeltaOrDefault() time.Duration {
+	if cc.ExpiryDelta > 0 {
+		return cc.ExpiryDelta
+	}
+	return DefaultOAuth2ExpiryDelta
+}
+
+// NowOrDefault returns the current time.
+func (cc *OAuth2ClientCredentials) NowOrDefault() time.Time {
+	if cc.Now != nil {
+		return cc.Now()
+	}
+	return time.Now().UTC()
+}
+
+// Token implements OAuth2TokenSource.
+func (cc *OAuth2ClientCredentials) Token(ctx context.Context) (*OAuth2Token, error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if cc.token != nil && cc.token.Valid(cc.NowOrDefault(), cc.ExpiryDeltaOrDefault()) {
+		return cc.token, nil
+	}
+	token, err := cc.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cc.token = token
+	return token, nil
+}
+
+// Invalidate implements OAuth2TokenSource.
+// It only clears the cached token if it is the given token, so concurrent
+// callers rejecting the same token trigger a single refresh.
+func (cc *OAuth2ClientCredentials) Invalidate(token *OAuth2Token) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if token == nil || (cc.token != nil && cc.token.AccessToken == token.AccessToken) {
+		cc.token = nil
+	}
+}
+
+func (cc *OAuth2ClientCredentials) fetch(ctx context.Context) (*OAuth2Token, error) {
+	clientID, err := resolveString(ctx, cc.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	clientSecret, err := resolveString(ctx, cc.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	for key, values := range cc.Params {
+		form[key] = values
+	}
+	form.Set("grant_type", "client_credentials")
+	if len(cc.Scopes) > 0 {
+		form.Set("scope", strings.Join(cc.Scopes, " "))
+	}
+
+	options := append([]Option{
+		OptPost(),
+		OptContext(ctx),
+		OptBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret)),
+		OptHeaderValue(HeaderContentType, ContentTypeApplicationFormEncoded),
+		OptPostForm(form),
+	}, cc.Options...)
+
+	started := cc.NowOrDefault()
+	contents, res, err := New(cc.TokenURL, options...).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode > 299 {
+		return nil, ex.New(ErrOAuth2TokenRequest, ex.OptMessagef("status: %d, body: %s", res.StatusCode, string(contents)))
+	}
+
+	var token OAuth2Token
+	if err = json.Unmarshal(contents, &token); err != nil {
+		return nil, ex.New(ErrOAuth2TokenRequest, ex.OptInner(err))
+	}
+	if token.AccessToken == "" {
+		return nil, ex.New(ErrOAuth2TokenRequest, ex.OptMessage("response did not include an access token"))
+	}
+	if token.ExpiresIn > 0 {
+		token.Expiry = started.Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return &token, nil
+}
+
+func resolveString(ctx context.Context, source configutil.StringSource) (string, error) {
+	if source == nil {
+		return "", nil
+	}
+	value, err := source.String(ctx)
+	if err != nil {
+		return "", err
+	}
+	if value == nil {
+		return "", nil
+	}
+	return *value, nil
+}
@@ -0,0 +1,141 @@
+package r2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/configutil"
+)
+
+func mockTokenServer(fetches *int32, expiresIn int64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		clientID, clientSecret, ok := req.BasicAuth()
+		if !ok || clientID != "client-id" || clientSecret != "client-secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if req.PostFormValue("grant_type") != "client_credentials" {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		count := atomic.AddInt32(fetches, 1)
+		rw.Header().Set(HeaderContentType, ContentTypeApplicationJSON)
+		json.NewEncoder(rw).Encode(OAuth2Token{
+			AccessToken: fmt.Sprintf("token-%d", count),
+			TokenType:   "Bearer",
+			ExpiresIn:   expiresIn,
+			Scope:       req.PostFormValue("scope"),
+		})
+	}))
+}
+
+func TestOAuth2ClientCredentialsToken(t *testing.T) {
+	assert := assert.New(t)
+
+	var fetches int32
+	server := mockTokenServer(&fetches, 3600)
+	defer server.Close()
+
+	cc := &OAuth2ClientCredentials{
+		TokenURL:     server.URL,
+		ClientID:     configutil.String("client-id"),
+		ClientSecret: configutil.String("client-secret"),
+		Scopes:       []string{"read", "write"},
+	}
+
+	token, err := cc.Token(context.TODO())
+	assert.Nil(err)
+	assert.Equal("token-1", token.AccessToken)
+	assert.Equal("read write", token.Scope)
+	assert.False(token.Expiry.IsZero())
+	assert.Equal("Bearer token-1", token.AuthorizationHeader())
+
+	token, err = cc.Token(context.TODO())
+	assert.Nil(err)
+	assert.Equal("token-1", token.AccessToken, "the token should be cached")
+	assert.Equal(1, atomic.LoadInt32(&fetches))
+
+	cc.Invalidate(&OAuth2Token{AccessToken: "some-other-token"})
+	token, err = cc.Token(context.TODO())
+	assert.Nil(err)
+	assert.Equal("token-1", token.AccessToken, "invalidating a different token should not clear the cache")
+
+	cc.Invalidate(token)
+	token, err = cc.Token(context.TODO())
+	assert.Nil(err)
+	assert.Equal("token-2", token.AccessToken)
+}
+
+func TestOAuth2ClientCredentialsTokenExpiry(t *testing.T) {
+	assert := assert.New(t)
+
+	var fetches int32
+	server := mockTokenServer(&fetches, 60)
+	defer server.Close()
+
+	now := time.Now().UTC()
+	cc := &OAuth2ClientCredentials{
+		TokenURL:     server.URL,
+		ClientID:     configutil.String("client-id"),
+		ClientSecret: configutil.String("client-secret"),
+		Now:          func() time.Time { return now },
+	}
+
+	token, err := cc.Token(context.TODO())
+	assert.Nil(err)
+	assert.Equal("token-1", token.AccessToken)
+
+	now = now.Add(45 * time.Second)
+	token, err = cc.Token(context.TODO())
+	assert.Nil(err)
+	assert.Equal("token-2", token.AccessToken, "tokens within the expiry delta should be refreshed")
+}
+
+func TestOAuth2ClientCredentialsTokenSingleFlight(t *testing.T) {
+	assert := assert.New(t)
+
+	var fetches int32
+	server := mockTokenServer(&fetches, 3600)
+	defer server.Close()
+
+	cc := &OAuth2ClientCredentials{
+		TokenURL:     server.URL,
+		ClientID:     configutil.String("client-id"),
+		ClientSecret: configutil.String("client-secret"),
+	}
+
+	wg := sync.WaitGroup{}
+	for x := 0; x < 16; x++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = cc.Token(context.TODO())
+		}()
+	}
+	wg.Wait()
+	assert.Equal(1, atomic.LoadInt32(&fetches))
+}
+
+func TestOAuth2ClientCredentialsTokenError(t *testing.T) {
+	assert := assert.New(t)
+
+	var fetches int32
+	server := mockTokenServer(&fetches, 3600)
+	defer server.Close()
+
+	cc := &OAuth2ClientCredentials{
+		TokenURL:     server.URL,
+		ClientID:     configutil.String("client-id"),
+		ClientSecret: configutil.String("wrong-secret"),
+	}
+	_, err := cc.Token(context.TODO())
+	assert.NotNil(err)
+}
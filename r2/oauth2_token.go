@@ -0,0 +1,49 @@
+package r2
+
+import (
+	"context"
+	"time"
+)
+
+// OAuth2TokenSource returns oauth2 tokens.
+type OAuth2TokenSource interface {
+	// Token returns a valid token, fetching a new one if required.
+	Token(context.Context) (*OAuth2Token, error)
+	// Invalidate marks a token as rejected so the next call to `Token` fetches a new one.
+	Invalidate(*OAuth2Token)
+}
+
+// OAuth2Token is an oauth2 access token.
+type OAuth2Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type,omitempty"`
+	ExpiresIn   int64  `json:"expires_in,omitempty"`
+	Scope       string `json:"scope,omitempty"`
+	// Expiry is computed from `ExpiresIn` when the token is fetched.
+	Expiry time.Time `json:"-"`
+}
+
+// TokenTypeOrDefault returns the token type or "Bearer".
+func (t OAuth2Token) TokenTypeOrDefault() string {
+	if t.TokenType != "" {
+		return t.TokenType
+	}
+	return "Bearer"
+}
+
+// AuthorizationHeader returns the value of the authorization header for the token.
+func (t OAuth2Token) AuthorizationHeader() string {
+	return t.TokenTypeOrDefault() + " " + t.AccessToken
+}
+
+// Valid returns if the token is set and not expiring within a given delta of a given time.
+// Tokens without an expiry never expire.
+func (t OAuth2Token) Valid(now time.Time, delta time.Duration) bool {
+	if t.AccessToken == "" {
+		return false
+	}
+	if t.Expiry.IsZero() {
+		return true
+	}
+	return now.Add(delta).Before(t.Expiry)
+}
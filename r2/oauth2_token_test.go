@@ -0,0 +1,26 @@
+package r2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestOAuth2TokenValid(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now().UTC()
+	assert.False(OAuth2Token{}.Valid(now, 0))
+	assert.True(OAuth2Token{AccessToken: "foo"}.Valid(now, time.Hour))
+	assert.True(OAuth2Token{AccessToken: "foo", Expiry: now.Add(time.Minute)}.Valid(now, time.Second))
+	assert.False(OAuth2Token{AccessToken: "foo", Expiry: now.Add(time.Minute)}.Valid(now, time.Minute))
+	assert.False(OAuth2Token{AccessToken: "foo", Expiry: now.Add(-time.Minute)}.Valid(now, 0))
+}
+
+func TestOAuth2TokenAuthorizationHeader(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("Bearer foo", OAuth2Token{AccessToken: "foo"}.AuthorizationHeader())
+	assert.Equal("MAC foo", OAuth2Token{AccessToken: "foo", TokenType: "MAC"}.AuthorizationHeader())
+}
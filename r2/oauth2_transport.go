@@ -0,0 +1,79 @@
+package r2
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+var (
+	_ http.RoundTripper = (*OAuth2Transport)(nil)
+)
+
+// OAuth2Transport is a round tripper that authorizes requests with tokens from a token source.
+//
+// If the server responds with a 401, the token is invalidated and the request is retried
+// once with a freshly fetched token, provided the request body can be replayed.
+type OAuth2Transport struct {
+	Source OAuth2TokenSource
+	// Base is the underlying transport, it defaults to `http.DefaultTransport`.
+	Base http.RoundTripper
+}
+
+// BaseOrDefault returns the base transport or a default.
+func (ot *OAuth2Transport) BaseOrDefault() http.RoundTripper {
+	if ot.Base != nil {
+		return ot.Base
+	}
+	return http.DefaultTransport
+}
+
+// RoundTrip implements http.RoundTripper.
+func (ot *OAuth2Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := ot.Source.Token(req.Context())
+	if err != nil {
+		closeRequestBody(req)
+		return nil, err
+	}
+	res, err := ot.BaseOrDefault().RoundTrip(authorizedRequest(req, token))
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return res, nil
+	}
+
+	ot.Source.Invalidate(token)
+	token, err = ot.Source.Token(req.Context())
+	if err != nil {
+		return res, nil
+	}
+	retry := authorizedRequest(req, token)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return res, nil
+		}
+	}
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	_ = res.Body.Close()
+	return ot.BaseOrDefault().RoundTrip(retry)
+}
+
+// authorizedRequest returns a shallow copy of a request with the authorization header set.
+// Round trippers must not modify the original request.
+func authorizedRequest(req *http.Request, token *OAuth2Token) *http.Request {
+	output := new(http.Request)
+	*output = *req
+	output.Header = make(http.Header, len(req.Header))
+	for key, values := range req.Header {
+		output.Header[key] = append([]string(nil), values...)
+	}
+	output.Header.Set(HeaderAuthorization, token.AuthorizationHeader())
+	return output
+}
+
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
@@ -0,0 +1,72 @@
+package r2
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// OptGZipBody compresses the request body with gzip and sets the `Content-Encoding` header.
+func OptGZipBody() Option {
+	return OptCompressBody(ContentEncodingGZIP)
+}
+
+// OptDeflateBody compresses the request body with deflate and sets the `Content-Encoding` header.
+func OptDeflateBody() Option {
+	return OptCompressBody(ContentEncodingDeflate)
+}
+
+// OptCompressBody compresses the request body with a given content encoding
+// and sets the `Content-Encoding` header.
+//
+// Compression happens when the request is sent, before it is signed, so the body can be set
+// and signers added by options applied before or after this one. Requests without a body are sent as is.
+func OptCompressBody(encoding string) Option {
+	return func(r *Request) error {
+		if encoding != ContentEncodingGZIP && encoding != ContentEncodingDeflate {
+			return ex.New(ErrContentEncodingUnsupported, ex.OptMessagef("encoding: %s", encoding))
+		}
+		return OptBodyTransform(func(req *http.Request) error {
+			return compressBody(req, encoding)
+		})(r)
+	}
+}
+
+func compressBody(req *http.Request, encoding string) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	defer req.Body.Close()
+
+	compressed := new(bytes.Buffer)
+	var writer io.WriteCloser
+	switch encoding {
+	case ContentEncodingGZIP:
+		writer = gzip.NewWriter(compressed)
+	default:
+		writer = zlib.NewWriter(compressed)
+	}
+	if _, err := io.Copy(writer, req.Body); err != nil {
+		return ex.New(err)
+	}
+	if err := writer.Close(); err != nil {
+		return ex.New(err)
+	}
+
+	contents := compressed.Bytes()
+	req.Body = ioutil.NopCloser(bytes.NewReader(contents))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(contents)), nil
+	}
+	req.ContentLength = int64(len(contents))
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+	req.Header.Set(HeaderContentEncoding, encoding)
+	return nil
+}
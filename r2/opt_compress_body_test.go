@@ -0,0 +1,95 @@
+package r2
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestOptCompressBody(t *testing.T) {
+	assert := assert.New(t)
+
+	var encoding, body string
+	var contentLength int64
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		encoding = req.Header.Get(HeaderContentEncoding)
+		contentLength = req.ContentLength
+
+		var reader io.Reader
+		var err error
+		switch encoding {
+		case ContentEncodingGZIP:
+			reader, err = gzip.NewReader(req.Body)
+		case ContentEncodingDeflate:
+			reader, err = zlib.NewReader(req.Body)
+		default:
+			reader = req.Body
+		}
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		contents, _ := ioutil.ReadAll(reader)
+		body = string(contents)
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	payload := bytes.Repeat([]byte("a very compressible payload "), 64)
+
+	_, err := New(server.URL, OptPost(), OptGZipBody(), OptBodyBytes(payload)).Discard()
+	assert.Nil(err)
+	assert.Equal(ContentEncodingGZIP, encoding)
+	assert.Equal(string(payload), body)
+	assert.True(contentLength < int64(len(payload)))
+
+	_, err = New(server.URL, OptPost(), OptBodyBytes(payload), OptDeflateBody()).Discard()
+	assert.Nil(err)
+	assert.Equal(ContentEncodingDeflate, encoding)
+	assert.Equal(string(payload), body)
+
+	_, err = New(server.URL, OptGZipBody()).Discard()
+	assert.Nil(err)
+	assert.Empty(encoding, "requests without bodies should not be encoded")
+}
+
+func TestOptCompressBodyUnsupported(t *testing.T) {
+	assert := assert.New(t)
+
+	req := New("http://foo.bar.local", OptCompressBody("br"))
+	assert.NotNil(req.Err)
+}
+
+func TestOptCompressBodySigned(t *testing.T) {
+	assert := assert.New(t)
+
+	signer := HMACSigner{KeyID: "test-key", Key: []byte("a secret key")}
+	var verifyErr error
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		signature, err := ParseHMACSignature(req.Header.Get(HeaderSignature))
+		if err == nil {
+			err = signer.Verify(req, signature)
+		}
+		verifyErr = err
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	payload := bytes.Repeat([]byte("a very compressible payload "), 64)
+
+	_, err := New(server.URL, OptPost(), OptHMACSigner(signer.KeyID, signer.Key), OptGZipBody(), OptBodyBytes(payload)).Discard()
+	assert.Nil(err)
+	assert.Nil(verifyErr, "signers added before compression should sign the compressed body")
+
+	verifyErr = nil
+	_, err = New(server.URL, OptPost(), OptBodyBytes(payload), OptGZipBody(), OptHMACSigner(signer.KeyID, signer.Key)).Discard()
+	assert.Nil(err)
+	assert.Nil(verifyErr, "signers added after compression should sign the compressed body")
+}
@@ -0,0 +1,155 @@
+package r2
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// OptDecompress requests gzip or deflate encoded responses and decodes them explicitly.
+//
+// Setting `Accept-Encoding` disables the transparent gzip handling of the http transport,
+// so this option decodes the response body itself. The `Content-Encoding` and `Content-Length`
+// headers are removed from decoded responses, and the response body is a `*DecodedBody`
+// that reports the original encoding and compressed size (these are also set on `Event`).
+//
+// The decoding listener runs before any other response listeners, regardless of option order.
+func OptDecompress() Option {
+	return func(r *Request) error {
+		if err := OptHeaderValue(HeaderAcceptEncoding, ContentEncodingGZIP+", "+ContentEncodingDeflate)(r); err != nil {
+			return err
+		}
+		r.OnResponse = append([]OnResponseListener{decompressResponse}, r.OnResponse...)
+		return nil
+	}
+}
+
+func decompressResponse(_ *http.Request, res *http.Response, _ time.Time, err error) error {
+	if err != nil || res == nil || res.Body == nil {
+		return err
+	}
+	encoding := strings.ToLower(strings.TrimSpace(res.Header.Get(HeaderContentEncoding)))
+	switch encoding {
+	case "", ContentEncodingIdentity:
+		return nil
+	case ContentEncodingGZIP, ContentEncodingDeflate:
+	default:
+		return ex.New(ErrContentEncodingUnsupported, ex.OptMessagef("encoding: %s", encoding))
+	}
+
+	res.Body = &DecodedBody{
+		Encoding:                encoding,
+		CompressedContentLength: res.ContentLength,
+		body:                    res.Body,
+	}
+	res.Header.Del(HeaderContentEncoding)
+	res.Header.Del(HeaderContentLength)
+	res.ContentLength = -1
+	res.Uncompressed = true
+	return nil
+}
+
+// DecodedBody is a response body decoded from a content encoding.
+// The decoder is created on the first read.
+type DecodedBody struct {
+	// Encoding is the original content encoding.
+	Encoding string
+	// CompressedContentLength is the original content length, or -1 if it was unknown.
+	CompressedContentLength int64
+
+	body    io.ReadCloser
+	counter *countingReader
+	decoder io.Reader
+	err     error
+	eof     bool
+}
+
+// CompressedSize returns the number of compressed bytes read once the body has been read in full,
+// and the original content length otherwise.
+func (db *DecodedBody) CompressedSize() int64 {
+	if db.eof && db.counter != nil {
+		return db.counter.count
+	}
+	return db.CompressedContentLength
+}
+
+// Read implements io.Reader.
+// If the decoder can't be created, the error is returned from every read.
+func (db *DecodedBody) Read(p []byte) (n int, err error) {
+	if db.err != nil {
+		return 0, db.err
+	}
+	if db.decoder == nil {
+		if err = db.initialize(); err != nil {
+			if err == io.EOF {
+				db.eof = true
+			}
+			db.err = err
+			return
+		}
+	}
+	n, err = db.decoder.Read(p)
+	if err == io.EOF {
+		db.eof = true
+	}
+	return
+}
+
+// Close implements io.Closer.
+func (db *DecodedBody) Close() error {
+	if closer, ok := db.decoder.(io.Closer); ok {
+		_ = closer.Close()
+	}
+	return db.body.Close()
+}
+
+func (db *DecodedBody) initialize() error {
+	db.counter = &countingReader{reader: db.body}
+	buffered := bufio.NewReader(db.counter)
+	switch db.Encoding {
+	case ContentEncodingGZIP:
+		decoder, err := gzip.NewReader(buffered)
+		if err != nil {
+			return err
+		}
+		db.decoder = decoder
+	default:
+		// "deflate" is meant to be zlib wrapped, but some servers send raw deflate streams.
+		header, err := buffered.Peek(2)
+		if err != nil {
+			return err
+		}
+		if !isZlibHeader(header) {
+			db.decoder = flate.NewReader(buffered)
+			return nil
+		}
+		decoder, err := zlib.NewReader(buffered)
+		if err != nil {
+			return err
+		}
+		db.decoder = decoder
+	}
+	return nil
+}
+
+func isZlibHeader(header []byte) bool {
+	return len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
+
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (cr *countingReader) Read(p []byte) (n int, err error) {
+	n, err = cr.reader.Read(p)
+	cr.count += int64(n)
+	return
+}
@@ -0,0 +1,120 @@
+package r2
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+)
+
+var decompressPayload = strings.Repeat("a very compressible payload ", 64)
+
+func mockServerEncoded() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		encoding := req.URL.Query().Get("encoding")
+		buffer := new(bytes.Buffer)
+		var writer io.WriteCloser
+		switch encoding {
+		case "gzip":
+			writer = gzip.NewWriter(buffer)
+		case "deflate":
+			writer = zlib.NewWriter(buffer)
+		case "raw-deflate":
+			writer, _ = flate.NewWriter(buffer, flate.DefaultCompression)
+			encoding = "deflate"
+		}
+		if writer != nil {
+			writer.Write([]byte(decompressPayload))
+			writer.Close()
+			rw.Header().Set(HeaderContentEncoding, encoding)
+		} else {
+			buffer.WriteString(decompressPayload)
+		}
+		rw.Header().Set(HeaderContentLength, strconv.Itoa(buffer.Len()))
+		rw.WriteHeader(http.StatusOK)
+		rw.Write(buffer.Bytes())
+	}))
+}
+
+func TestOptDecompress(t *testing.T) {
+	assert := assert.New(t)
+
+	server := mockServerEncoded()
+	defer server.Close()
+
+	for _, encoding := range []string{"gzip", "deflate", "raw-deflate", "identity"} {
+		contents, res, err := New(server.URL+"?encoding="+encoding, OptDecompress()).Bytes()
+		assert.Nil(err, encoding)
+		assert.Equal(decompressPayload, string(contents), encoding)
+		assert.Empty(res.Header.Get(HeaderContentEncoding), encoding)
+	}
+}
+
+func TestOptDecompressEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	server := mockServerEncoded()
+	defer server.Close()
+
+	var event Event
+	log := logger.None()
+	log.Flags.Enable(FlagResponse)
+	log.Listen(FlagResponse, "test", func(_ context.Context, e logger.Event) {
+		event = e.(Event)
+	})
+
+	// the logging option is applied first to verify decoding runs before it regardless.
+	contents, _, err := New(server.URL+"?encoding=gzip", OptLogResponseWithBody(log), OptDecompress()).Bytes()
+	assert.Nil(err)
+	log.Drain()
+
+	assert.Equal(decompressPayload, string(contents))
+	assert.Equal(decompressPayload, string(event.Body))
+	assert.Equal(ContentEncodingGZIP, event.ContentEncoding)
+	assert.True(event.CompressedContentLength > 0)
+	assert.True(event.CompressedContentLength < int64(len(decompressPayload)))
+	assert.Equal(ContentEncodingGZIP, event.Decompose()["res"].(map[string]interface{})["contentEncoding"])
+}
+
+func TestOptDecompressUnsupported(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set(HeaderContentEncoding, "br")
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL, OptDecompress()).Discard()
+	assert.NotNil(err)
+}
+
+func TestDecodedBodyInitializeError(t *testing.T) {
+	assert := assert.New(t)
+
+	body := &DecodedBody{
+		Encoding: ContentEncodingGZIP,
+		body:     ioutil.NopCloser(strings.NewReader("not a gzip stream, but long enough to be read as a header")),
+	}
+	buffer := make([]byte, 16)
+	_, err := body.Read(buffer)
+	assert.Equal(gzip.ErrHeader, err)
+	consumed := body.counter.count
+
+	n, err := body.Read(buffer)
+	assert.Zero(n)
+	assert.Equal(gzip.ErrHeader, err, "later reads should return the initialization error")
+	assert.Equal(consumed, body.counter.count, "later reads should not read from the stream")
+	assert.Nil(body.Close())
+}
@@ -0,0 +1,24 @@
+package r2
+
+import (
+	"net/http"
+
+	"github.com/blend/go-sdk/logger"
+)
+
+// OptLogCurl adds an OnRequest listener that logs the request as an equivalent curl command at the debug level.
+func OptLogCurl(log logger.Log, options ...CurlOption) Option {
+	return func(r *Request) error {
+		return OptOnRequest(func(req *http.Request) error {
+			sent := Request{Request: *req, Client: r.Client}
+			command, err := sent.Curl(options...)
+			if err != nil {
+				return err
+			}
+			// the body is replaced with an in memory copy when rendering.
+			req.Body = sent.Request.Body
+			logger.MaybeDebugfContext(req.Context(), log, "%s", command)
+			return nil
+		})(r)
+	}
+}
@@ -0,0 +1,34 @@
+package r2
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+)
+
+func TestOptLogCurl(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	log, err := logger.New(logger.OptOutput(buf), logger.OptAll())
+	assert.Nil(err)
+
+	var body string
+	server := mockServerOK()
+	defer server.Close()
+
+	_, err = New(server.URL, OptPost(), OptBasicAuth("user", "pass"), OptBodyBytes([]byte("request body")), OptLogCurl(log, OptCurlRedact()), OptOnRequest(func(req *http.Request) error {
+		body = readString(req.Body)
+		req.Body = ioutil.NopCloser(bytes.NewBufferString(body))
+		return nil
+	})).Discard()
+	assert.Nil(err)
+	assert.Equal("request body", body)
+
+	log.Drain()
+	assert.Contains(buf.String(), "curl -X POST -L -H 'Authorization: REDACTED' --data-binary 'request body'")
+}
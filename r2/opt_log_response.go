@@ -48,9 +48,6 @@ func OptLogResponseWithBody(log logger.Triggerable) Option {
 		if _, err := io.Copy(buffer, res.Body); err != nil {
 			return err
 		}
-		// set the body to the read contents
-		res.Body = ioutil.NopCloser(bytes.NewReader(buffer.Bytes()))
-
 		event := NewEvent(FlagResponse,
 			OptEventRequest(req),
 			OptEventResponse(res),
@@ -58,6 +55,9 @@ func OptLogResponseWithBody(log logger.Triggerable) Option {
 			OptEventElapsed(time.Now().UTC().Sub(started)),
 		)
 
+		// set the body to the read contents
+		res.Body = ioutil.NopCloser(bytes.NewReader(buffer.Bytes()))
+
 		logger.MaybeTrigger(req.Context(), log, event)
 		return nil
 	})
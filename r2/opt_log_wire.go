@@ -0,0 +1,40 @@
+package r2
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"time"
+
+	"github.com/blend/go-sdk/logger"
+)
+
+// OptLogWire adds OnRequest and OnResponse listeners that log the full wire format
+// of the request and response, including bodies, at the debug level.
+//
+// Bodies are read in full to produce the dump; do not use this if bodies can be large.
+// Secrets in headers are logged as is.
+func OptLogWire(log logger.Log) Option {
+	return func(r *Request) error {
+		if err := OptOnRequest(func(req *http.Request) error {
+			dump, err := httputil.DumpRequestOut(req, true)
+			if err != nil {
+				return err
+			}
+			logger.MaybeDebugfContext(req.Context(), log, "%s", dump)
+			return nil
+		})(r); err != nil {
+			return err
+		}
+		return OptOnResponse(func(req *http.Request, res *http.Response, _ time.Time, err error) error {
+			if err != nil {
+				return err
+			}
+			dump, err := httputil.DumpResponse(res, true)
+			if err != nil {
+				return err
+			}
+			logger.MaybeDebugfContext(req.Context(), log, "%s", dump)
+			return nil
+		})(r)
+	}
+}
@@ -0,0 +1,31 @@
+package r2
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+)
+
+func TestOptLogWire(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	log, err := logger.New(logger.OptOutput(buf), logger.OptAll())
+	assert.Nil(err)
+
+	server := mockServerOK()
+	defer server.Close()
+
+	contents, _, err := New(server.URL, OptPost(), OptHeaderValue("X-Foo", "bar"), OptBodyBytes([]byte("request body")), OptLogWire(log)).Bytes()
+	assert.Nil(err)
+	assert.Equal("OK!\n", string(contents))
+
+	log.Drain()
+	assert.Contains(buf.String(), "POST / HTTP/1.1")
+	assert.Contains(buf.String(), "X-Foo: bar")
+	assert.Contains(buf.String(), "request body")
+	assert.Contains(buf.String(), "HTTP/1.1 200 OK")
+	assert.Contains(buf.String(), "OK!")
+}
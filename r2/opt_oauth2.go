@@ -0,0 +1,12 @@
+package r2
+
+// OptOAuth2 authorizes the request with tokens from a given token source.
+//
+// The client transport is wrapped with an `OAuth2Transport` when the request is sent, so options
+// that configure the transport (e.g. `OptTLSSkipVerify` or `OptTransport`) can be applied in any order.
+func OptOAuth2(source OAuth2TokenSource) Option {
+	return func(r *Request) error {
+		r.OAuth2 = source
+		return nil
+	}
+}
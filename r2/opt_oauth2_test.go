@@ -0,0 +1,98 @@
+package r2
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/configutil"
+)
+
+func TestOptOAuth2(t *testing.T) {
+	assert := assert.New(t)
+
+	var fetches int32
+	tokenServer := mockTokenServer(&fetches, 3600)
+	defer tokenServer.Close()
+
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		authorizations = append(authorizations, req.Header.Get(HeaderAuthorization))
+		// reject the first token to exercise the retry.
+		if req.Header.Get(HeaderAuthorization) == "Bearer token-1" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+		rw.Write([]byte(readString(req.Body)))
+	}))
+	defer server.Close()
+
+	source := &OAuth2ClientCredentials{
+		TokenURL:     tokenServer.URL,
+		ClientID:     configutil.String("client-id"),
+		ClientSecret: configutil.String("client-secret"),
+	}
+
+	contents, res, err := New(server.URL, OptPost(), OptBodyBytes([]byte("hello")), OptOAuth2(source)).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal("hello", string(contents))
+	assert.Equal([]string{"Bearer token-1", "Bearer token-2"}, authorizations)
+
+	res, err = New(server.URL, OptOAuth2(source)).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal(2, atomic.LoadInt32(&fetches))
+}
+
+func TestOptOAuth2TokenError(t *testing.T) {
+	assert := assert.New(t)
+
+	server := mockServerOK()
+	defer server.Close()
+
+	source := &OAuth2ClientCredentials{
+		TokenURL: "http://127.0.0.1:0/",
+	}
+	_, err := New(server.URL, OptOAuth2(source)).Discard()
+	assert.NotNil(err)
+
+	token, err := source.Token(context.TODO())
+	assert.NotNil(err)
+	assert.Nil(token)
+}
+
+func TestOptOAuth2TransportOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	var fetches int32
+	tokenServer := mockTokenServer(&fetches, 3600)
+	defer tokenServer.Close()
+
+	var authorization string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		authorization = req.Header.Get(HeaderAuthorization)
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	source := &OAuth2ClientCredentials{
+		TokenURL:     tokenServer.URL,
+		ClientID:     configutil.String("client-id"),
+		ClientSecret: configutil.String("client-secret"),
+	}
+
+	req := New(server.URL, OptOAuth2(source), OptTLSSkipVerify(true))
+	assert.Nil(req.Err)
+	_, ok := req.Client.Transport.(*http.Transport)
+	assert.True(ok, "the client transport should not be wrapped by the option")
+
+	res, err := req.Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal("Bearer token-1", authorization)
+}
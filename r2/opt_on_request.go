@@ -12,3 +12,12 @@ func OptOnRequest(listener OnRequestListener) Option {
 		return nil
 	}
 }
+
+// OptBodyTransform adds a hook that transforms the request body before it is sent.
+// Body transforms run before any on request listeners, including signers, regardless of option order.
+func OptBodyTransform(transform OnRequestListener) Option {
+	return func(r *Request) error {
+		r.BodyTransforms = append(r.BodyTransforms, transform)
+		return nil
+	}
+}
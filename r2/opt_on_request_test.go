@@ -2,6 +2,7 @@ package r2
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/blend/go-sdk/assert"
@@ -16,3 +17,26 @@ func TestOptOnRequest(t *testing.T) {
 	)
 	assert.Len(r.OnRequest, 2)
 }
+
+func TestOptBodyTransform(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL,
+		OptOnRequest(func(_ *http.Request) error {
+			calls = append(calls, "on request")
+			return nil
+		}),
+		OptBodyTransform(func(_ *http.Request) error {
+			calls = append(calls, "body transform")
+			return nil
+		}),
+	).Discard()
+	assert.Nil(err)
+	assert.Equal([]string{"body transform", "on request"}, calls)
+}
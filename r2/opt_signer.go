@@ -0,0 +1,13 @@
+package r2
+
+// OptSigner adds an OnRequest listener that signs the request.
+// Signing happens when the request is sent, after all options have been applied
+// and the body has been transformed, e.g. compressed.
+func OptSigner(signer Signer) Option {
+	return OptOnRequest(signer.Sign)
+}
+
+// OptHMACSigner signs the request with an hmac-sha256 signature using a given key id and key.
+func OptHMACSigner(keyID string, key []byte, headers ...string) Option {
+	return OptSigner(HMACSigner{KeyID: keyID, Key: key, Headers: headers})
+}
@@ -0,0 +1,34 @@
+package r2
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestOptSigner(t *testing.T) {
+	assert := assert.New(t)
+
+	var didSign bool
+	r := New("http://foo.com", OptSigner(SignerFunc(func(_ *http.Request) error {
+		didSign = true
+		return nil
+	})))
+	assert.Len(r.OnRequest, 1)
+	assert.Nil(r.OnRequest[0](&r.Request))
+	assert.True(didSign)
+}
+
+func TestOptHMACSigner(t *testing.T) {
+	assert := assert.New(t)
+
+	r := New("http://foo.com", OptHMACSigner("test-key", []byte("a secret key"), "Content-Type"))
+	assert.Len(r.OnRequest, 1)
+	assert.Nil(r.OnRequest[0](&r.Request))
+
+	signature, err := ParseHMACSignature(r.Header.Get(HeaderSignature))
+	assert.Nil(err)
+	assert.Equal("test-key", signature.KeyID)
+	assert.True(stringsContain(signature.Headers, "content-type"))
+}
@@ -0,0 +1,210 @@
+package r2test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// Expectation is a request the stub server expects, and the response it gives.
+type Expectation struct {
+	Method  string
+	Path    string
+	Query   map[string]string
+	Headers http.Header
+	// BodyMatcher returns a reason the body does not match, or an empty string if it does.
+	BodyMatcher func([]byte) string
+	// Times is the number of times the expectation should be matched.
+	// A negative value matches any number of times.
+	Times int
+
+	StatusCode int
+	Response   http.Header
+	Body       []byte
+	Latency    time.Duration
+	// Failure closes the connection without a response.
+	Failure bool
+
+	calls int32
+}
+
+// String returns a description of the expectation.
+func (e *Expectation) String() string {
+	return fmt.Sprintf("%s %s", e.Method, e.Path)
+}
+
+// Calls returns the number of times the expectation was matched.
+func (e *Expectation) Calls() int {
+	return int(atomic.LoadInt32(&e.calls))
+}
+
+// WithQuery adds an expected query string value.
+func (e *Expectation) WithQuery(key, value string) *Expectation {
+	if e.Query == nil {
+		e.Query = make(map[string]string)
+	}
+	e.Query[key] = value
+	return e
+}
+
+// WithHeader adds an expected header value.
+func (e *Expectation) WithHeader(key, value string) *Expectation {
+	e.Headers.Set(key, value)
+	return e
+}
+
+// WithBody sets an expected exact body.
+func (e *Expectation) WithBody(body string) *Expectation {
+	e.BodyMatcher = func(actual []byte) string {
+		if string(actual) != body {
+			return fmt.Sprintf("body: expected %q, actual %q", body, string(actual))
+		}
+		return ""
+	}
+	return e
+}
+
+// WithJSONBody sets an expected json body.
+// The request body matches if it decodes to a value equal to the given object's json form,
+// so field order and whitespace don't matter.
+func (e *Expectation) WithJSONBody(obj interface{}) *Expectation {
+	e.BodyMatcher = func(actual []byte) string {
+		expectedJSON, err := json.Marshal(obj)
+		if err != nil {
+			return fmt.Sprintf("json body: cannot marshal expected: %v", err)
+		}
+		var expected, decoded interface{}
+		_ = json.Unmarshal(expectedJSON, &expected)
+		if err := json.Unmarshal(actual, &decoded); err != nil {
+			return fmt.Sprintf("json body: cannot unmarshal actual %q: %v", string(actual), err)
+		}
+		if !reflect.DeepEqual(expected, decoded) {
+			return fmt.Sprintf("json body: expected %s, actual %s", string(expectedJSON), string(actual))
+		}
+		return ""
+	}
+	return e
+}
+
+// WithBodyMatcher sets a custom body matcher.
+func (e *Expectation) WithBodyMatcher(matcher func([]byte) string) *Expectation {
+	e.BodyMatcher = matcher
+	return e
+}
+
+// Once sets the expectation to match exactly once.
+func (e *Expectation) Once() *Expectation {
+	return e.times(1)
+}
+
+// Twice sets the expectation to match exactly twice.
+func (e *Expectation) Twice() *Expectation {
+	return e.times(2)
+}
+
+// TimesN sets the expectation to match exactly n times.
+func (e *Expectation) TimesN(n int) *Expectation {
+	return e.times(n)
+}
+
+// AnyTimes sets the expectation to match any number of times, including none.
+func (e *Expectation) AnyTimes() *Expectation {
+	return e.times(-1)
+}
+
+// Respond sets the response status code and body.
+func (e *Expectation) Respond(statusCode int, body string) *Expectation {
+	e.StatusCode = statusCode
+	e.Body = []byte(body)
+	return e
+}
+
+// RespondJSON sets the response status code and a json body.
+func (e *Expectation) RespondJSON(statusCode int, obj interface{}) *Expectation {
+	contents, err := json.Marshal(obj)
+	if err != nil {
+		panic(err)
+	}
+	e.StatusCode = statusCode
+	e.Body = contents
+	e.Response.Set("Content-Type", "application/json; charset=UTF-8")
+	return e
+}
+
+// RespondHeader adds a response header.
+func (e *Expectation) RespondHeader(key, value string) *Expectation {
+	e.Response.Add(key, value)
+	return e
+}
+
+// RespondAfter delays the response by a given latency.
+func (e *Expectation) RespondAfter(latency time.Duration) *Expectation {
+	e.Latency = latency
+	return e
+}
+
+// Fail closes the connection without responding, causing a client error.
+func (e *Expectation) Fail() *Expectation {
+	e.Failure = true
+	return e
+}
+
+func (e *Expectation) times(n int) *Expectation {
+	e.Times = n
+	return e
+}
+
+// exhausted returns if the expectation has been matched the expected number of times.
+func (e *Expectation) exhausted() bool {
+	return e.Times >= 0 && e.Calls() >= e.Times
+}
+
+// mismatch returns a reason a request does not match, or an empty string if it does.
+func (e *Expectation) mismatch(req *http.Request, body []byte) string {
+	if e.Method != "" && req.Method != e.Method {
+		return fmt.Sprintf("method: expected %q, actual %q", e.Method, req.Method)
+	}
+	if e.Path != "" && req.URL.Path != e.Path {
+		return fmt.Sprintf("path: expected %q, actual %q", e.Path, req.URL.Path)
+	}
+	query := req.URL.Query()
+	for key, value := range e.Query {
+		if actual := query.Get(key); actual != value {
+			return fmt.Sprintf("query %s: expected %q, actual %q", key, value, actual)
+		}
+	}
+	for key := range e.Headers {
+		if expected, actual := e.Headers.Get(key), req.Header.Get(key); actual != expected {
+			return fmt.Sprintf("header %s: expected %q, actual %q", key, expected, actual)
+		}
+	}
+	if e.BodyMatcher != nil {
+		return e.BodyMatcher(body)
+	}
+	return ""
+}
+
+func (e *Expectation) respond(rw http.ResponseWriter) {
+	if e.Latency > 0 {
+		time.Sleep(e.Latency)
+	}
+	if e.Failure {
+		if hijacker, ok := rw.(http.Hijacker); ok {
+			if conn, _, err := hijacker.Hijack(); err == nil {
+				_ = conn.Close()
+				return
+			}
+		}
+		panic(http.ErrAbortHandler)
+	}
+	for key, values := range e.Response {
+		for _, value := range values {
+			rw.Header().Add(key, value)
+		}
+	}
+	rw.WriteHeader(e.StatusCode)
+	_, _ = rw.Write(e.Body)
+}
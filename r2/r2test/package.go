@@ -0,0 +1,19 @@
+/*
+Package r2test provides a stub http server for testing code that makes requests with r2.
+
+Tests declare the requests they expect, and the responses to give, fluently:
+
+	server := r2test.New(assert.New(t))
+	defer server.Close()
+
+	server.Expect("POST", "/users").
+		WithHeader("Authorization", "Bearer token").
+		WithJSONBody(map[string]interface{}{"email": "foo@bar.com"}).
+		RespondJSON(http.StatusCreated, User{ID: 1})
+
+	user, err := NewClient(server.URL).CreateUser("foo@bar.com")
+
+Closing the server asserts that each expectation was matched the expected number of times,
+and that no requests were made that did not match an expectation.
+*/
+package r2test
@@ -0,0 +1,127 @@
+package r2test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/r2"
+)
+
+// New returns a new, started, stub server.
+// Expectations are verified with the given assertions when the server is closed.
+func New(a *assert.Assertions) *Server {
+	s := &Server{
+		Assert: a,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Server is a stub http server that responds to requests that match declared expectations.
+//
+// Requests that don't match any expectation are answered with a 501 and reported,
+// along with why each expectation didn't match, when the server is verified.
+type Server struct {
+	*httptest.Server
+	Assert *assert.Assertions
+
+	mu           sync.Mutex
+	expectations []*Expectation
+	mismatches   []string
+}
+
+// OptURL returns an r2 option that sets the request url to the server url with a given path.
+func (s *Server) OptURL(path string) r2.Option {
+	return r2.OptURL(s.URL + path)
+}
+
+// Expect adds an expectation for a given method and path.
+// By default the expectation must be matched exactly once and responds with an empty 200.
+func (s *Server) Expect(method, path string) *Expectation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := &Expectation{
+		Method:     method,
+		Path:       path,
+		Headers:    http.Header{},
+		Times:      1,
+		StatusCode: http.StatusOK,
+		Response:   http.Header{},
+	}
+	s.expectations = append(s.expectations, e)
+	return e
+}
+
+// Mismatches returns descriptions of requests that did not match any expectation.
+func (s *Server) Mismatches() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.mismatches...)
+}
+
+// Verify asserts that each expectation was matched the expected number of times
+// and that every request matched an expectation.
+func (s *Server) Verify() {
+	if s.Assert == nil {
+		return
+	}
+	mismatches := s.Mismatches()
+	s.Assert.Empty(mismatches, "unexpected requests:\n", strings.Join(mismatches, "\n"))
+
+	s.mu.Lock()
+	expectations := append([]*Expectation(nil), s.expectations...)
+	s.mu.Unlock()
+	for _, e := range expectations {
+		if e.Times < 0 {
+			continue
+		}
+		s.Assert.Equal(e.Times, e.Calls(), fmt.Sprintf("call count for %s", e))
+	}
+}
+
+// Close closes the server and verifies expectations.
+func (s *Server) Close() {
+	s.Server.Close()
+	s.Verify()
+}
+
+func (s *Server) handle(rw http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.mu.Lock()
+	var match *Expectation
+	var reasons []string
+	for _, e := range s.expectations {
+		if e.exhausted() {
+			reasons = append(reasons, fmt.Sprintf("\t%s: already matched %d time(s)", e, e.Calls()))
+			continue
+		}
+		if reason := e.mismatch(req, body); reason != "" {
+			reasons = append(reasons, fmt.Sprintf("\t%s: %s", e, reason))
+			continue
+		}
+		match = e
+		atomic.AddInt32(&e.calls, 1)
+		break
+	}
+	if match == nil {
+		s.mismatches = append(s.mismatches, fmt.Sprintf("%s %s\n%s", req.Method, req.URL.RequestURI(), strings.Join(reasons, "\n")))
+	}
+	s.mu.Unlock()
+
+	if match == nil {
+		http.Error(rw, "r2test: no expectation matched the request", http.StatusNotImplemented)
+		return
+	}
+	match.respond(rw)
+}
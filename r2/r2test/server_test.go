@@ -0,0 +1,118 @@
+package r2test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/r2"
+)
+
+func TestServer(t *testing.T) {
+	assert := assert.New(t)
+
+	server := New(assert)
+	defer server.Close()
+
+	server.Expect("POST", "/users").
+		WithQuery("dry_run", "false").
+		WithHeader("X-Foo", "bar").
+		WithJSONBody(map[string]interface{}{"email": "foo@bar.com", "admin": false}).
+		RespondJSON(http.StatusCreated, map[string]interface{}{"id": 1}).
+		RespondHeader("X-Request-Id", "abc")
+
+	server.Expect("GET", "/users/1").
+		Twice().
+		Respond(http.StatusOK, "OK!")
+
+	var created struct {
+		ID int `json:"id"`
+	}
+	res, err := r2.New(server.URL+"/users?dry_run=false",
+		r2.OptPost(),
+		r2.OptHeaderValue("X-Foo", "bar"),
+		r2.OptBodyBytes([]byte(`{ "admin": false, "email": "foo@bar.com" }`)),
+	).JSON(&created)
+	assert.Nil(err)
+	assert.Equal(http.StatusCreated, res.StatusCode)
+	assert.Equal("abc", res.Header.Get("X-Request-Id"))
+	assert.Equal(1, created.ID)
+
+	for x := 0; x < 2; x++ {
+		contents, res, err := r2.New("", server.OptURL("/users/1")).Bytes()
+		assert.Nil(err)
+		assert.Equal(http.StatusOK, res.StatusCode)
+		assert.Equal("OK!", string(contents))
+	}
+}
+
+func TestServerMismatch(t *testing.T) {
+	output := new(bytes.Buffer)
+	server := New(assert.Empty(assert.OptOutput(output)))
+
+	assert := assert.New(t)
+	server.Expect("GET", "/foo").WithHeader("X-Foo", "bar")
+
+	res, err := r2.New(server.URL+"/foo", r2.OptHeaderValue("X-Foo", "baz")).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotImplemented, res.StatusCode)
+
+	mismatches := server.Mismatches()
+	assert.Len(mismatches, 1)
+	assert.Contains(mismatches[0], `header X-Foo: expected "bar", actual "baz"`)
+
+	var didPanic bool
+	func() {
+		defer func() {
+			didPanic = recover() != nil
+		}()
+		server.Close()
+	}()
+	assert.True(didPanic)
+	assert.Contains(output.String(), "unexpected requests")
+}
+
+func TestServerCallCount(t *testing.T) {
+	output := new(bytes.Buffer)
+	server := New(assert.Empty(assert.OptOutput(output)))
+
+	assert := assert.New(t)
+	server.Expect("GET", "/foo").TimesN(2)
+	server.Expect("GET", "/bar").AnyTimes()
+
+	_, err := r2.New(server.URL + "/foo").Discard()
+	assert.Nil(err)
+
+	var didPanic bool
+	func() {
+		defer func() {
+			didPanic = recover() != nil
+		}()
+		server.Close()
+	}()
+	assert.True(didPanic)
+	assert.Contains(output.String(), "call count for GET /foo")
+}
+
+func TestServerFailureAndLatency(t *testing.T) {
+	assert := assert.New(t)
+
+	server := New(assert)
+	defer server.Close()
+
+	server.Expect("GET", "/fail").Fail()
+	server.Expect("GET", "/slow").TimesN(2).RespondAfter(50 * time.Millisecond)
+
+	_, err := r2.New(server.URL + "/fail").Discard()
+	assert.NotNil(err)
+
+	started := time.Now()
+	_, err = r2.New(server.URL + "/slow").Discard()
+	assert.Nil(err)
+	assert.True(time.Since(started) >= 50*time.Millisecond)
+
+	_, err = r2.New(server.URL+"/slow", r2.OptTimeout(time.Millisecond)).Discard()
+	assert.NotNil(err)
+}
@@ -50,6 +50,12 @@ type Request struct {
 	Closer func() error
 	// Tracer is used to report span contexts to a distributed tracing collector.
 	Tracer Tracer
+	// OAuth2 is an optional token source requests are authorized with.
+	// The client transport is wrapped with an `OAuth2Transport` when the request is sent.
+	OAuth2 OAuth2TokenSource
+	// BodyTransforms are hooks that transform the request body before it is sent, e.g. compression.
+	// They run before the OnRequest listeners, so signers see the body as it is sent.
+	BodyTransforms []OnRequestListener
 	// OnRequest is an array of request lifecycle hooks used for logging.
 	OnRequest []OnRequestListener
 	// OnResponse is an array of response lifecycle hooks used for logging.
@@ -70,6 +76,11 @@ func (r Request) Do() (*http.Response, error) {
 	}
 
 	var err error
+	for _, transform := range r.BodyTransforms {
+		if err = transform(&r.Request); err != nil {
+			return nil, err
+		}
+	}
 	started := time.Now().UTC()
 
 	var finisher TraceFinisher
@@ -83,12 +94,21 @@ func (r Request) Do() (*http.Response, error) {
 		}
 	}
 
-	var res *http.Response
-	if r.Client != nil {
-		res, err = r.Client.Do(&r.Request)
-	} else {
-		res, err = http.DefaultClient.Do(&r.Request)
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
 	}
+	if r.OAuth2 != nil {
+		authorized := *client
+		authorized.Transport = &OAuth2Transport{
+			Source: r.OAuth2,
+			Base:   client.Transport,
+		}
+		client = &authorized
+	}
+
+	var res *http.Response
+	res, err = client.Do(&r.Request)
 	if finisher != nil {
 		finisher.Finish(&r.Request, res, started, err)
 	}
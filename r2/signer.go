@@ -0,0 +1,140 @@
+package r2
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// Signer signs outgoing requests, typically by adding headers computed from the request contents.
+type Signer interface {
+	Sign(*http.Request) error
+}
+
+// SignerFunc is a function that implements Signer.
+type SignerFunc func(*http.Request) error
+
+// Sign implements Signer.
+func (sf SignerFunc) Sign(req *http.Request) error {
+	return sf(req)
+}
+
+// HashBody returns the hex encoded sha256 of the request body.
+// The body is read in full and replaced with an in memory copy so it can be sent (or handled) afterwards.
+func HashBody(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		sum := sha256.Sum256(nil)
+		return hex.EncodeToString(sum[:]), nil
+	}
+	contents, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return "", ex.New(err)
+	}
+	_ = req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(contents))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(contents)), nil
+	}
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// CanonicalRequest returns the canonical form of a request as signed by the signers in this package.
+//
+// It is the method, the escaped path, the sorted query string, each signed header as `name:value`,
+// the signed header names joined with ";", and the payload hash, separated by newlines.
+// This matches the canonical request of AWS Signature Version 4.
+func CanonicalRequest(req *http.Request, signedHeaders []string, payloadHash string) string {
+	return strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(req.URL),
+		canonicalHeaders(req, signedHeaders),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+}
+
+// normalizeHeaderNames lowercases, dedupes and sorts a list of header names.
+func normalizeHeaderNames(names ...string) []string {
+	seen := make(map[string]bool)
+	var output []string
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		output = append(output, name)
+	}
+	sort.Strings(output)
+	return output
+}
+
+func canonicalURI(u *url.URL) string {
+	if u == nil {
+		return "/"
+	}
+	if path := u.EscapedPath(); path != "" {
+		return path
+	}
+	return "/"
+}
+
+func canonicalQuery(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string{}, query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, canonicalEscape(key)+"="+canonicalEscape(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func canonicalEscape(value string) string {
+	return strings.Replace(url.QueryEscape(value), "+", "%20", -1)
+}
+
+// canonicalHeaders returns the signed headers as `name:value` lines.
+// The host is read from the request host, falling back to the url host, so that
+// client and server requests produce the same value.
+func canonicalHeaders(req *http.Request, signedHeaders []string) string {
+	lines := make([]string, 0, len(signedHeaders))
+	for _, name := range signedHeaders {
+		var value string
+		if name == "host" {
+			value = req.Host
+			if value == "" && req.URL != nil {
+				value = req.URL.Host
+			}
+		} else {
+			var values []string
+			for _, v := range req.Header[http.CanonicalHeaderKey(name)] {
+				values = append(values, strings.Join(strings.Fields(v), " "))
+			}
+			value = strings.Join(values, ",")
+		}
+		lines = append(lines, name+":"+value)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
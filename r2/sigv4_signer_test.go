@@ -0,0 +1,47 @@
+package r2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestSigV4Signer(t *testing.T) {
+	assert := assert.New(t)
+
+	// this is the `get-vanilla` case from the aws signature v4 test suite.
+	signer := SigV4Signer{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:          "us-east-1",
+		Service:         "service",
+		Now:             func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) },
+	}
+
+	req := New("https://example.amazonaws.com/")
+	assert.Nil(req.Err)
+	assert.Nil(signer.Sign(&req.Request))
+
+	assert.Equal("20150830T123600Z", req.Header.Get(HeaderAmzDate))
+	assert.Equal("AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31", req.Header.Get(HeaderAuthorization))
+}
+
+func TestSigV4SignerSessionToken(t *testing.T) {
+	assert := assert.New(t)
+
+	signer := SigV4Signer{
+		AccessKeyID:       "AKIDEXAMPLE",
+		SecretAccessKey:   "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		SessionToken:      "a session token",
+		Region:            "us-east-1",
+		Service:           "s3",
+		SignContentSHA256: true,
+	}
+
+	req := New("https://example.amazonaws.com/", OptPut(), OptBodyBytes([]byte("hello")))
+	assert.Nil(signer.Sign(&req.Request))
+	assert.Equal("a session token", req.Header.Get(HeaderAmzSecurityToken))
+	assert.Equal("2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", req.Header.Get(HeaderAmzContentSHA256))
+	assert.Contains(req.Header.Get(HeaderAuthorization), "SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token,")
+}
@@ -0,0 +1,37 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blend/go-sdk/configutil"
+)
+
+var (
+	_ configutil.StringSource = (*StringSource)(nil)
+)
+
+// StringSource is a configutil string source that reads a field of a secret.
+// It returns nil if the field is not present.
+type StringSource struct {
+	KV    KV
+	Key   string
+	Field string
+}
+
+// String implements configutil.StringSource.
+func (ss StringSource) String(ctx context.Context) (*string, error) {
+	values, err := ss.KV.Get(ctx, ss.Key)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := values[ss.Field]
+	if !ok || value == nil {
+		return nil, nil
+	}
+	if typed, ok := value.(string); ok {
+		return &typed, nil
+	}
+	output := fmt.Sprint(value)
+	return &output, nil
+}
@@ -0,0 +1,50 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+type mockKV map[string]Values
+
+func (mkv mockKV) Put(_ context.Context, key string, data Values, _ ...RequestOption) error {
+	mkv[key] = data
+	return nil
+}
+
+func (mkv mockKV) Get(_ context.Context, key string, _ ...RequestOption) (Values, error) {
+	return mkv[key], nil
+}
+
+func (mkv mockKV) Delete(_ context.Context, key string, _ ...RequestOption) error {
+	delete(mkv, key)
+	return nil
+}
+
+func (mkv mockKV) List(_ context.Context, _ string, _ ...RequestOption) ([]string, error) {
+	return nil, nil
+}
+
+func TestStringSource(t *testing.T) {
+	assert := assert.New(t)
+
+	kv := mockKV{
+		"/oauth/client": Values{"client_id": "my-client", "port": 8080},
+	}
+
+	value, err := StringSource{KV: kv, Key: "/oauth/client", Field: "client_id"}.String(context.TODO())
+	assert.Nil(err)
+	assert.NotNil(value)
+	assert.Equal("my-client", *value)
+
+	value, err = StringSource{KV: kv, Key: "/oauth/client", Field: "port"}.String(context.TODO())
+	assert.Nil(err)
+	assert.NotNil(value)
+	assert.Equal("8080", *value)
+
+	value, err = StringSource{KV: kv, Key: "/oauth/client", Field: "missing"}.String(context.TODO())
+	assert.Nil(err)
+	assert.Nil(value)
+}
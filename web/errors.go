@@ -3,6 +3,7 @@ package web
 import (
 	"github.com/blend/go-sdk/ex"
 	"github.com/blend/go-sdk/jwt"
+	"github.com/blend/go-sdk/r2"
 )
 
 const (
@@ -14,6 +15,12 @@ const (
 	ErrUnsetViewTemplate ex.Class = "view result template is unset"
 	// ErrParameterMissing is an error on request validation.
 	ErrParameterMissing ex.Class = "parameter is missing"
+	// ErrSignatureExpired is an error returned if a request signature timestamp is outside the allowed skew.
+	ErrSignatureExpired ex.Class = "request signature is expired"
+	// ErrSignatureReplayed is an error returned if a request signature nonce has already been seen.
+	ErrSignatureReplayed ex.Class = "request signature has already been used"
+	// ErrSignatureKeyUnknown is an error returned if a request signature key id is not known.
+	ErrSignatureKeyUnknown ex.Class = "request signature key is unknown"
 )
 
 // NewParameterMissingError returns a new parameter missing error.
@@ -41,3 +48,14 @@ func IsErrParameterMissing(err error) bool {
 	}
 	return ex.Is(err, ErrParameterMissing)
 }
+
+// IsErrSignatureInvalid returns if an error is a request signature verification error.
+func IsErrSignatureInvalid(err error) bool {
+	if err == nil {
+		return false
+	}
+	return r2.IsErrSignature(err) ||
+		ex.Is(err, ErrSignatureExpired) ||
+		ex.Is(err, ErrSignatureReplayed) ||
+		ex.Is(err, ErrSignatureKeyUnknown)
+}
@@ -0,0 +1,123 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blend/go-sdk/cache"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/r2"
+)
+
+const (
+	// DefaultSignatureSkew is the default maximum difference between a signature timestamp and the server time.
+	DefaultSignatureSkew = 5 * time.Minute
+)
+
+// SignatureKeyProvider returns the key for a given key id.
+// It should return a nil key if the key id is unknown.
+type SignatureKeyProvider func(ctx context.Context, keyID string) ([]byte, error)
+
+// SignatureVerifier verifies request signatures produced by `r2.HMACSigner`.
+type SignatureVerifier struct {
+	// KeyProvider returns the key for a given key id.
+	KeyProvider SignatureKeyProvider
+	// Skew is the maximum difference allowed between the signature timestamp and now.
+	// It defaults to `DefaultSignatureSkew`.
+	Skew time.Duration
+	// Nonces is a cache of recently seen nonces used to reject replayed requests.
+	// If it is a `*cache.LocalCache` it should be started so expired nonces are swept.
+	// If unset, replays within the skew window are not detected.
+	Nonces cache.Cache
+	// Headers are additional headers that must be included in the signature.
+	Headers []string
+	// Now returns the current time, it defaults to `time.Now`.
+	Now func() time.Time
+}
+
+// SkewOrDefault returns the skew or a default.
+func (sv SignatureVerifier) SkewOrDefault() time.Duration {
+	if sv.Skew > 0 {
+		return sv.Skew
+	}
+	return DefaultSignatureSkew
+}
+
+// NowOrDefault returns the current time.
+func (sv SignatureVerifier) NowOrDefault() time.Time {
+	if sv.Now != nil {
+		return sv.Now()
+	}
+	return time.Now().UTC()
+}
+
+// Verify verifies the signature on a request.
+func (sv SignatureVerifier) Verify(req *http.Request) error {
+	signature, err := r2.ParseHMACSignature(req.Header.Get(r2.HeaderSignature))
+	if err != nil {
+		return err
+	}
+
+	timestamp, err := strconv.ParseInt(req.Header.Get(r2.HeaderSignatureTimestamp), 10, 64)
+	if err != nil {
+		return ex.New(r2.ErrSignatureMalformed, ex.OptMessage("invalid timestamp"))
+	}
+	skew := sv.NowOrDefault().Sub(time.Unix(timestamp, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > sv.SkewOrDefault() {
+		return ex.New(ErrSignatureExpired, ex.OptMessagef("skew: %v", skew))
+	}
+
+	if sv.KeyProvider == nil {
+		return ex.New(ErrSignatureKeyUnknown, ex.OptMessagef("key id: %s", signature.KeyID))
+	}
+	key, err := sv.KeyProvider(req.Context(), signature.KeyID)
+	if err != nil {
+		return err
+	}
+	if len(key) == 0 {
+		return ex.New(ErrSignatureKeyUnknown, ex.OptMessagef("key id: %s", signature.KeyID))
+	}
+
+	signer := r2.HMACSigner{
+		KeyID:     signature.KeyID,
+		Key:       key,
+		Algorithm: signature.Algorithm,
+		Headers:   sv.Headers,
+	}
+	if err = signer.Verify(req, signature); err != nil {
+		return err
+	}
+
+	// check the nonce last so that unsigned requests can't fill the cache.
+	if sv.Nonces != nil {
+		nonceKey := signature.KeyID + ":" + req.Header.Get(r2.HeaderSignatureNonce)
+		_, seen, _ := sv.Nonces.GetOrSet(nonceKey, func() (interface{}, error) {
+			return true, nil
+		}, cache.OptValueTTL(2*sv.SkewOrDefault()))
+		if seen {
+			return ex.New(ErrSignatureReplayed)
+		}
+	}
+	return nil
+}
+
+// SignatureRequired returns a middleware that rejects requests without a valid signature.
+// Invalid, expired or replayed signatures result in a not authorized response.
+func SignatureRequired(verifier SignatureVerifier) Middleware {
+	return func(action Action) Action {
+		return func(ctx *Ctx) Result {
+			if err := verifier.Verify(ctx.Request); err != nil {
+				if IsErrSignatureInvalid(err) {
+					return ctx.DefaultProvider.NotAuthorized()
+				}
+				return ctx.DefaultProvider.InternalError(err)
+			}
+			return action(ctx)
+		}
+	}
+}
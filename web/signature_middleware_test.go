@@ -0,0 +1,89 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cache"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/r2"
+)
+
+func testSignatureKeys(_ context.Context, keyID string) ([]byte, error) {
+	if keyID == "test-key" {
+		return []byte("a secret key"), nil
+	}
+	return nil, nil
+}
+
+func TestSignatureRequired(t *testing.T) {
+	assert := assert.New(t)
+
+	var didExecuteHandler bool
+	app := MustNew()
+	app.POST("/", func(r *Ctx) Result {
+		didExecuteHandler = true
+		body, err := r.PostBody()
+		if err != nil {
+			return Text.InternalError(err)
+		}
+		return Text.Result(string(body))
+	}, SignatureRequired(SignatureVerifier{KeyProvider: testSignatureKeys}))
+
+	body, meta, err := MockMethod(app, "POST", "/", r2.OptBodyBytes([]byte("hello")), r2.OptHMACSigner("test-key", []byte("a secret key"))).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("hello", string(body))
+	assert.True(didExecuteHandler)
+
+	didExecuteHandler = false
+	meta, err = MockMethod(app, "POST", "/", r2.OptBodyBytes([]byte("hello"))).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+	assert.False(didExecuteHandler)
+
+	meta, err = MockMethod(app, "POST", "/", r2.OptBodyBytes([]byte("hello")), r2.OptHMACSigner("test-key", []byte("wrong key"))).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+	assert.False(didExecuteHandler)
+
+	meta, err = MockMethod(app, "POST", "/", r2.OptBodyBytes([]byte("hello")), r2.OptHMACSigner("unknown-key", []byte("a secret key"))).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+	assert.False(didExecuteHandler)
+}
+
+func TestSignatureVerifierSkew(t *testing.T) {
+	assert := assert.New(t)
+
+	signer := r2.HMACSigner{
+		KeyID: "test-key",
+		Key:   []byte("a secret key"),
+		Now:   func() time.Time { return time.Now().UTC().Add(-time.Hour) },
+	}
+	req := r2.New("http://foo.bar.local/")
+	assert.Nil(signer.Sign(&req.Request))
+
+	err := SignatureVerifier{KeyProvider: testSignatureKeys}.Verify(&req.Request)
+	assert.True(IsErrSignatureInvalid(err))
+	assert.Nil(SignatureVerifier{KeyProvider: testSignatureKeys, Skew: 2 * time.Hour}.Verify(&req.Request))
+}
+
+func TestSignatureVerifierReplay(t *testing.T) {
+	assert := assert.New(t)
+
+	req := r2.New("http://foo.bar.local/")
+	assert.Nil(r2.HMACSigner{KeyID: "test-key", Key: []byte("a secret key")}.Sign(&req.Request))
+
+	verifier := SignatureVerifier{
+		KeyProvider: testSignatureKeys,
+		Nonces:      cache.NewLocalCache(),
+	}
+	assert.Nil(verifier.Verify(&req.Request))
+	err := verifier.Verify(&req.Request)
+	assert.True(IsErrSignatureInvalid(err))
+	assert.True(ex.Is(err, ErrSignatureReplayed))
+}